@@ -0,0 +1,53 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// AmazonWAFResult is a solved Amazon WAF captcha
+type AmazonWAFResult struct {
+	CaptchaVoucher string `json:"captcha_voucher"`
+	ExistingToken  string `json:"existing_token"`
+}
+
+// SolveAmazonWAF performs an Amazon WAF captcha solving request to 2captcha.com
+// and returns with the solved captcha and captcha ID if the request was successful.
+// siteKey, iv and wafContext are the key, iv and context values of the
+// captcha page, they change with every page load.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#amazon-waf
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type AmazonTaskProxyless.
+func (c *TwoCaptchaClient) SolveAmazonWAF(siteKey, iv, wafContext, siteURL string, delay time.Duration, retries int, opts ...SolveOption) (AmazonWAFResult, string, error) {
+	return c.SolveAmazonWAFWithContext(context.Background(), siteKey, iv, wafContext, siteURL, delay, retries, opts...)
+}
+
+// SolveAmazonWAFWithContext is SolveAmazonWAF with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type AmazonTaskProxyless.
+func (c *TwoCaptchaClient) SolveAmazonWAFWithContext(ctx context.Context, siteKey, iv, wafContext, siteURL string, delay time.Duration, retries int, opts ...SolveOption) (AmazonWAFResult, string, error) {
+	res, err := c.solve(
+		ctx,
+		map[string]string{
+			"sitekey": siteKey,
+			"iv":      iv,
+			"context": wafContext,
+			"pageurl": siteURL,
+			"method":  "amazon_waf",
+		},
+		delay,
+		retries,
+		opts,
+	)
+	if err != nil {
+		return AmazonWAFResult{}, res.ID, err
+	}
+	var result AmazonWAFResult
+	if err := parseObjectAnswer(res.Answer, &result); err != nil {
+		return result, res.ID, errors.New("Invalid Amazon WAF answer: " + res.Answer)
+	}
+	return result, res.ID, nil
+}
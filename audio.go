@@ -0,0 +1,108 @@
+package twocaptcha
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// maxAudioSize is the maximum size of an audio captcha accepted by 2captcha
+const maxAudioSize = 1024 * 1024
+
+// TranscriptNormalization is a set of transformations applied to the text of
+// an audio captcha. Flags can be combined, e.g.
+// TranscriptStripPunctuation | TranscriptLowercase
+type TranscriptNormalization int
+
+const (
+	// TranscriptAsIs returns the text as typed by the worker, this is the default
+	TranscriptAsIs TranscriptNormalization = 0
+	// TranscriptStripPunctuation removes the punctuation and collapses the spaces
+	TranscriptStripPunctuation TranscriptNormalization = 1
+	// TranscriptDigitsOnly keeps only the digits, the English number words
+	// zero to nine are converted to digits first, e.g. "five 3 two" is "532"
+	TranscriptDigitsOnly TranscriptNormalization = 2
+	// TranscriptLowercase converts the text to lower case
+	TranscriptLowercase TranscriptNormalization = 4
+)
+
+// WithTranscriptNormalization sets how the text of an audio captcha is
+// normalized to match what the target form expects
+func WithTranscriptNormalization(n TranscriptNormalization) SolveOption {
+	return func(o *solveOptions) {
+		o.transcript = n
+	}
+}
+
+// numberWords are the English number words converted by TranscriptDigitsOnly
+var numberWords = map[string]string{
+	"zero": "0", "oh": "0", "one": "1", "two": "2", "three": "3", "four": "4",
+	"five": "5", "six": "6", "seven": "7", "eight": "8", "nine": "9",
+}
+
+// normalizeTranscript applies the transformations of n to the text of an
+// audio captcha
+func normalizeTranscript(text string, n TranscriptNormalization) string {
+	if n&TranscriptDigitsOnly != 0 {
+		var b strings.Builder
+		for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return unicode.IsSpace(r) || unicode.IsPunct(r)
+		}) {
+			if digit, ok := numberWords[word]; ok {
+				word = digit
+			}
+			for _, r := range word {
+				if unicode.IsDigit(r) {
+					b.WriteRune(r)
+				}
+			}
+		}
+		return b.String()
+	}
+	if n&TranscriptStripPunctuation != 0 {
+		text = strings.Join(strings.Fields(strings.Map(func(r rune) rune {
+			if unicode.IsPunct(r) {
+				return ' '
+			}
+			return r
+		}, text)), " ")
+	}
+	if n&TranscriptLowercase != 0 {
+		text = strings.ToLower(text)
+	}
+	return text
+}
+
+// SolveAudioCaptcha performs an audio captcha solving request to 2captcha.com
+// and returns with the text of the recording if the request was successful.
+// audio is an mp3 recording, lang is its language: en, fr, de, el, pt or ru.
+// The workers are given lang as the hint of the language, the text is
+// normalized with WithTranscriptNormalization.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#audio
+func (c *TwoCaptchaClient) SolveAudioCaptcha(ctx context.Context, audio []byte, lang string, opts ...SolveOption) (CaptchaResult, error) {
+	if len(audio) == 0 {
+		return CaptchaResult{}, errors.New("Audio captcha is empty")
+	}
+	if len(audio) > maxAudioSize {
+		return CaptchaResult{}, errors.New("Audio captcha is too large")
+	}
+	if lang == "" {
+		return CaptchaResult{}, errors.New("Language of the audio captcha is required")
+	}
+	params := map[string]string{
+		"method": "audio",
+		"body":   base64.StdEncoding.EncodeToString(audio),
+		"lang":   lang,
+	}
+
+	delay, retries := c.polling()
+	res, err := c.solveProxyless(ctx, params, delay, retries, opts)
+	if err != nil {
+		return res, err
+	}
+	res.Answer = normalizeTranscript(res.Answer, c.solveOptions(opts).transcript)
+	return res, nil
+}
@@ -0,0 +1,137 @@
+package twocaptcha
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// awaitMinWait is the first polling interval of Await
+	awaitMinWait = 5 * time.Second
+	// awaitMaxWait is the longest polling interval of Await
+	awaitMaxWait = 30 * time.Second
+)
+
+// Await polls the result of a submitted captcha until it is solved or ctx is done.
+// The polling interval starts at 5 seconds and grows up to 30 seconds.
+// The Backoff of the client replaces the default intervals if it is set.
+// onPoll is called before every poll with the number of the attempt, it can be nil.
+func (c *TwoCaptchaClient) Await(ctx context.Context, captchaId string, onPoll func(attempt int)) (CaptchaResult, error) {
+	ctx, done, err := c.begin(ctx)
+	if err != nil {
+		return CaptchaResult{ID: captchaId}, err
+	}
+	defer done()
+
+	return c.await(ctx, captchaId, onPoll, nil)
+}
+
+// PollEvent is the outcome of a poll of a captcha sent by PollResult
+type PollEvent struct {
+	// Status is StatusPending if the captcha is not solved yet,
+	// StatusSolved or StatusFailed after the last poll
+	Status Status
+	// Attempt is the number of the poll, starting at 1
+	Attempt int
+	// Result is the solved captcha, StatusSolved only
+	Result CaptchaResult
+	// Err is the error of the poll. It is ErrNotReady for StatusPending,
+	// unless the poll failed with a network error and is retried.
+	Err error
+}
+
+// PollResult polls the result of a submitted captcha like Await and sends
+// the outcome of every poll on the returned channel, e.g. to drive the
+// solves from an event loop. The channel is closed after the StatusSolved or
+// StatusFailed event, or when ctx is done without sending the last event.
+func (c *TwoCaptchaClient) PollResult(ctx context.Context, captchaId string) <-chan PollEvent {
+	events := make(chan PollEvent, 1)
+	send := func(e PollEvent) {
+		select {
+		case events <- e:
+		case <-ctx.Done():
+		}
+	}
+	go func() {
+		defer close(events)
+		ctx, done, err := c.begin(ctx)
+		if err != nil {
+			send(PollEvent{Status: StatusFailed, Result: CaptchaResult{ID: captchaId}, Err: err})
+			return
+		}
+		defer done()
+
+		attempts := 0
+		res, err := c.await(ctx, captchaId, func(attempt int) {
+			attempts = attempt
+		}, func(err error) {
+			send(PollEvent{Status: StatusPending, Attempt: attempts, Err: err})
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				send(PollEvent{Status: StatusFailed, Attempt: attempts, Result: res, Err: err})
+			}
+			return
+		}
+		send(PollEvent{Status: StatusSolved, Attempt: attempts, Result: res})
+	}()
+	return events
+}
+
+// await polls the result of a captcha, onPoll is called before every poll
+// and notReady after the polls which did not return the answer, both can be nil
+func (c *TwoCaptchaClient) await(ctx context.Context, captchaId string, onPoll func(attempt int), notReady func(err error)) (CaptchaResult, error) {
+	wait := awaitMinWait
+	for attempt := 1; ; attempt++ {
+		if c.Backoff != nil && attempt > 1 {
+			wait = c.Backoff(attempt - 1)
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return CaptchaResult{ID: captchaId}, err
+		}
+		if onPoll != nil {
+			onPoll(attempt)
+		}
+		res, err := c.do(ctx, c.resultURL(), map[string]string{
+			"id":     captchaId,
+			"action": c.getAction(ctx),
+		})
+		if err == ErrNotReady || (isTransient(err) && ctx.Err() == nil) {
+			if notReady != nil {
+				notReady(err)
+			}
+			if wait = wait * 3 / 2; wait > awaitMaxWait {
+				wait = awaitMaxWait
+			}
+			continue
+		}
+		if err != nil {
+			return CaptchaResult{ID: captchaId}, err
+		}
+		return c.solved(ctx, captchaId, res), nil
+	}
+}
+
+// GetSolution fetches the answer of a captcha again with a single poll of
+// res.php, e.g. when the goroutine of the solve died after the captcha was
+// solved but before its answer was stored. 2captcha keeps the answers for a
+// short time only, ERROR_WRONG_CAPTCHA_ID is returned afterwards and
+// ErrNotReady if the captcha is not solved yet. The cost of the captcha is
+// not counted again.
+func (c *TwoCaptchaClient) GetSolution(ctx context.Context, captchaId string) (CaptchaResult, error) {
+	ctx, done, err := c.begin(ctx)
+	if err != nil {
+		return CaptchaResult{ID: captchaId}, err
+	}
+	defer done()
+
+	res, err := c.do(ctx, c.resultURL(), map[string]string{
+		"id":     captchaId,
+		"action": c.getAction(ctx),
+	})
+	if err != nil {
+		return CaptchaResult{ID: captchaId}, err
+	}
+	res.Polls = 1
+	return captchaResult(ctx, captchaId, res), nil
+}
@@ -0,0 +1,58 @@
+package twocaptcha
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Backoff returns the wait after the poll attempt of a captcha which was
+// not ready, before the next poll. attempt starts at 1. The first poll
+// follows the initial wait of the solve.
+type Backoff func(attempt int) time.Duration
+
+// ConstantBackoff waits d between the polls
+func ConstantBackoff(d time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		return d
+	}
+}
+
+// ExponentialBackoff waits initial after the first poll and doubles
+// the wait after every attempt up to max
+func ExponentialBackoff(initial, max time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		d := initial
+		for i := 1; i < attempt && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		return d
+	}
+}
+
+// JitterBackoff randomizes the waits of b between the half and the full
+// wait, spreading the polls of captchas submitted at the same time
+func JitterBackoff(b Backoff) Backoff {
+	return func(attempt int) time.Duration {
+		d := b(attempt)
+		if d <= 1 {
+			return d
+		}
+		return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+	}
+}
+
+// wait returns the wait after the poll attempt. The Backoff of the client is
+// used if it is set, delay seconds otherwise. A zero delay never waits, it is
+// used by the requests which are not polls.
+func (c *TwoCaptchaClient) wait(delay time.Duration, attempt int) time.Duration {
+	if delay <= 0 {
+		return 0
+	}
+	if c.Backoff != nil {
+		return c.Backoff(attempt)
+	}
+	return delay * time.Second
+}
@@ -0,0 +1,43 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// GetBalance returns the balance of the account in USD.
+// See more details on https://2captcha.com/2captcha-api#additional-methods
+func (c *TwoCaptchaClient) GetBalance() (float64, error) {
+	return c.GetBalanceWithContext(context.Background())
+}
+
+// GetBalanceWithContext is GetBalance with a context
+func (c *TwoCaptchaClient) GetBalanceWithContext(ctx context.Context) (float64, error) {
+	ctx, done, err := c.begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer done()
+
+	// the text format returns the balance without the OK| prefix,
+	// the JSON format is requested to detect the errors reliably
+	ctx = context.WithValue(ctx, formatKey{}, JSONFormat)
+	res, err := c.apiRequest(
+		ctx,
+		c.resultURL(),
+		map[string]string{
+			"action": "getbalance",
+		},
+		0,
+		3,
+	)
+	if err != nil {
+		return 0, err
+	}
+	balance, err := strconv.ParseFloat(res.Answer, 64)
+	if err != nil {
+		return 0, errors.New("Invalid balance: " + res.Answer)
+	}
+	return balance, nil
+}
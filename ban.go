@@ -0,0 +1,115 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+)
+
+// ErrCoolingDown is returned by the submissions of a client cooling down
+// after an IP ban or a rate limit error of the API, see Status
+var ErrCoolingDown = errors.New("Client is cooling down after an IP ban of 2captcha")
+
+// banCooldowns are the default cool-downs of the ban and rate limit errors
+var banCooldowns = map[string]time.Duration{
+	CodeIPBanned:        5 * time.Minute,
+	CodeErrorIPBanned:   5 * time.Minute,
+	CodeIPBlocked:       5 * time.Minute,
+	CodeTooMuchRequests: 30 * time.Second,
+	CodeMaxUserTurn:     10 * time.Second,
+}
+
+// limitCodes are the cool-downs communicated by the API with the numeric
+// codes of ERROR_TOO_MUCH_REQUESTS
+var limitCodes = map[string]time.Duration{
+	"1001": 10 * time.Minute,
+	"1002": 5 * time.Minute,
+	"1003": 30 * time.Second,
+	"1004": 10 * time.Minute,
+	"1005": 5 * time.Minute,
+}
+
+// limitCodePattern matches the numeric codes of ERROR_TOO_MUCH_REQUESTS
+var limitCodePattern = regexp.MustCompile(`\b100[1-5]\b`)
+
+// ClientStatus is the state of a client returned by Status
+type ClientStatus struct {
+	// Banned is true while the client is cooling down after an IP ban or a
+	// rate limit error, the captchas are not submitted until Until
+	Banned bool
+	// Code is the error code of the ban, e.g. IP_BANNED
+	Code string
+	// Until is the end of the cool-down
+	Until time.Time
+}
+
+// WithBanCooldown sets the cool-down after an IP ban or a rate limit error
+// of the API. The cool-downs communicated by the API with the error take
+// precedence. By default the client cools down 5 minutes after the IP bans,
+// e.g. IP_BANNED or ERROR_IP_BLOCKED, 30 seconds after ERROR_TOO_MUCH_REQUESTS
+// and 10 seconds after MAX_USER_TURN.
+func WithBanCooldown(d time.Duration) Option {
+	return func(c *TwoCaptchaClient) {
+		c.banCooldown = d
+	}
+}
+
+// Status returns the state of the client
+func (c *TwoCaptchaClient) Status() ClientStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if time.Now().Before(c.ban.Until) {
+		return c.ban
+	}
+	return ClientStatus{}
+}
+
+// noteBan starts the cool-down of the client if err is an IP ban or a rate
+// limit error and calls OnBan
+func (c *TwoCaptchaClient) noteBan(err error) {
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		return
+	}
+	// the numeric codes are sent as ERROR: 1001 in the text format
+	code := limitCodePattern.FindString(apiErr.Code + " " + apiErr.Description)
+	cooldown, ok := banCooldowns[apiErr.Code]
+	if !ok && code == "" {
+		return
+	}
+	if c.banCooldown > 0 {
+		cooldown = c.banCooldown
+	}
+	if code != "" {
+		cooldown = limitCodes[code]
+	}
+
+	c.mu.Lock()
+	until := time.Now().Add(cooldown)
+	if until.Before(c.ban.Until) {
+		c.mu.Unlock()
+		return
+	}
+	c.ban = ClientStatus{Banned: true, Code: apiErr.Code, Until: until}
+	status := c.ban
+	c.mu.Unlock()
+
+	c.logf("2captcha: %s, cooling down until %s", apiErr.Code, until.Format(time.RFC3339))
+	if c.OnBan != nil {
+		c.OnBan(status)
+	}
+}
+
+// cooldown returns ErrCoolingDown for the submissions while the client is
+// cooling down, the other requests wait for the end of the cool-down
+func (c *TwoCaptchaClient) cooldown(ctx context.Context, submission bool) error {
+	s := c.Status()
+	if !s.Banned {
+		return nil
+	}
+	if submission {
+		return ErrCoolingDown
+	}
+	return sleep(ctx, time.Until(s.Until))
+}
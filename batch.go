@@ -0,0 +1,228 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// reportConcurrency is the number of concurrent requests of ReportBadBatch
+	// and ReportGoodBatch
+	reportConcurrency = 5
+	// defaultBatchConcurrency is the default concurrency of BatchSolver
+	defaultBatchConcurrency = 10
+)
+
+// BatchError is returned by the functions solving multiple captchas at once
+// if some of the solves failed. It holds the error of each solve by index,
+// nil for the successful ones.
+type BatchError []error
+
+func (e BatchError) Error() string {
+	failed := 0
+	var first error
+	for _, err := range e {
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			failed++
+		}
+	}
+	return fmt.Sprintf("%d of %d solves failed, first error: %v", failed, len(e), first)
+}
+
+// SolveRecaptchaV2N solves n reCAPTCHA v2 captchas of the same site concurrently
+// and returns with the n tokens. reCAPTCHA tokens are single use, so every
+// token comes from a separate solve. The concurrency limit of the client is
+// respected. If some of the solves fail, the tokens of the successful ones
+// are returned together with a BatchError, failed tokens are left empty.
+// No token is returned for n == 0, an error is returned for a negative n.
+//
+// Deprecated: use a BatchSolver with RecaptchaV2Task tasks.
+func (c *TwoCaptchaClient) SolveRecaptchaV2N(siteURL, recaptchaKey string, opts RecaptchaOptions, n int, delay time.Duration, retries int, solveOpts ...SolveOption) ([]string, error) {
+	if n < 0 {
+		return nil, errors.New("Number of captchas must not be negative")
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	tokens := make([]string, n)
+	errs := make(BatchError, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], _, errs[i] = c.SolveRecaptcha(siteURL, recaptchaKey, opts, delay, retries, solveOpts...)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return tokens, errs
+		}
+	}
+	return tokens, nil
+}
+
+// ReportBadBatch reports multiple incorrectly solved captchas to 2captcha.com
+// concurrently and returns with the error of each report by index.
+// The rate limits of the client are respected. The captchas are reported
+// with ApiKey, use ReportResults for the captchas solved with the other keys
+// of WithAPIKeys.
+func (c *TwoCaptchaClient) ReportBadBatch(ids []string) []error {
+	return c.ReportResults(context.Background(), idResults(ids), false)
+}
+
+// ReportGoodBatch reports multiple correctly solved captchas to 2captcha.com
+// concurrently and returns with the error of each report by index.
+// The rate limits of the client are respected. The captchas are reported
+// with ApiKey, use ReportResults for the captchas solved with the other keys
+// of WithAPIKeys.
+func (c *TwoCaptchaClient) ReportGoodBatch(ids []string) []error {
+	return c.ReportResults(context.Background(), idResults(ids), true)
+}
+
+// ReportResults reports multiple solved captchas with ReportResult
+// concurrently and returns with the error of each report by index.
+// The rate limits of the client are respected.
+func (c *TwoCaptchaClient) ReportResults(ctx context.Context, results []CaptchaResult, correct bool) []error {
+	errs := make([]error, len(results))
+	slots := make(chan struct{}, reportConcurrency)
+	var wg sync.WaitGroup
+	for i, res := range results {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, res CaptchaResult) {
+			defer wg.Done()
+			errs[i] = c.ReportResult(ctx, res, correct)
+			<-slots
+		}(i, res)
+	}
+	wg.Wait()
+	return errs
+}
+
+// idResults returns the results of the captcha IDs solved with ApiKey
+func idResults(ids []string) []CaptchaResult {
+	res := make([]CaptchaResult, len(ids))
+	for i, id := range ids {
+		res[i] = CaptchaResult{ID: id}
+	}
+	return res
+}
+
+// BatchResult is the result of a task solved by a BatchSolver
+type BatchResult struct {
+	// Index is the position of the task in the input of the solver
+	Index int
+	// Task is the solved task
+	Task Task
+	// Result is the solved captcha, it holds the ID of the last attempt on errors
+	Result CaptchaResult
+	// Err is the error of the last attempt
+	Err error
+	// Attempts is the number of solves of the task
+	Attempts int
+}
+
+// BatchSolver solves tasks concurrently with SolveTask and streams the
+// results. The concurrency limit of the client is respected as well.
+type BatchSolver struct {
+	// Client solves the tasks
+	Client *TwoCaptchaClient
+	// Concurrency is the number of tasks solved at the same time, 10 by default
+	Concurrency int
+	// Retries is the number of additional solves of a failed task
+	Retries int
+	// Retry decides whether a failed task is solved again.
+	// DefaultBatchRetry is used if it is nil.
+	Retry func(err error) bool
+}
+
+// DefaultBatchRetry retries the tasks failed with ErrCaptchaUnsolvable or
+// ErrNoSlotAvailable. The timed out tasks are not retried, they are still
+// being solved and would be paid for twice, their answer can be collected
+// with WaitResult and the ID of the Result.
+func DefaultBatchRetry(err error) bool {
+	return errors.Is(err, ErrCaptchaUnsolvable) || errors.Is(err, ErrNoSlotAvailable)
+}
+
+// Solve solves the tasks received from tasks until it is closed or ctx is
+// done. The results are sent on the returned channel in the order of
+// completion, it is closed after the last result. The results must be
+// received until the channel is closed.
+func (b *BatchSolver) Solve(ctx context.Context, tasks <-chan Task) <-chan BatchResult {
+	concurrency := b.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+	results := make(chan BatchResult)
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	go func() {
+		defer close(results)
+		defer wg.Wait()
+		for i := 0; ; i++ {
+			var task Task
+			var ok bool
+			select {
+			case task, ok = <-tasks:
+			case <-ctx.Done():
+			}
+			if !ok {
+				return
+			}
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			wg.Add(1)
+			go func(i int, task Task) {
+				defer wg.Done()
+				r := b.solve(ctx, i, task)
+				<-slots
+				results <- r
+			}(i, task)
+		}
+	}()
+	return results
+}
+
+// SolveAll solves tasks with Solve
+func (b *BatchSolver) SolveAll(ctx context.Context, tasks []Task) <-chan BatchResult {
+	ch := make(chan Task)
+	go func() {
+		defer close(ch)
+		for _, task := range tasks {
+			select {
+			case ch <- task:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return b.Solve(ctx, ch)
+}
+
+// solve solves a task with the retry policy of the solver
+func (b *BatchSolver) solve(ctx context.Context, i int, task Task) BatchResult {
+	retry := b.Retry
+	if retry == nil {
+		retry = DefaultBatchRetry
+	}
+	r := BatchResult{Index: i, Task: task}
+	for r.Attempts <= b.Retries {
+		r.Attempts++
+		r.Result, r.Err = b.Client.SolveTask(ctx, task)
+		if r.Err == nil || ctx.Err() != nil || !retry(r.Err) {
+			break
+		}
+	}
+	return r
+}
@@ -0,0 +1,46 @@
+package twocaptcha
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"image"
+	"time"
+)
+
+// SolveBoundingBox performs a bounding box captcha solving request to
+// 2captcha.com and returns with the rectangles drawn by the worker around
+// the objects and captcha ID if the request was successful. instructions
+// tells the worker what to select, e.g. "select all billboards".
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#bounding_box
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type BoundingBoxTask.
+func (c *TwoCaptchaClient) SolveBoundingBox(img []byte, instructions string, delay time.Duration, retries int, opts ...SolveOption) ([]image.Rectangle, string, error) {
+	return c.SolveBoundingBoxWithContext(context.Background(), img, instructions, delay, retries, opts...)
+}
+
+// SolveBoundingBoxWithContext is SolveBoundingBox with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type BoundingBoxTask.
+func (c *TwoCaptchaClient) SolveBoundingBoxWithContext(ctx context.Context, img []byte, instructions string, delay time.Duration, retries int, opts ...SolveOption) ([]image.Rectangle, string, error) {
+	if instructions == "" {
+		return nil, "", errors.New("Instructions are required for bounding box captchas")
+	}
+	if err := validateImage(img); err != nil {
+		return nil, "", err
+	}
+	params := map[string]string{
+		"method":           "base64",
+		"body":             base64.StdEncoding.EncodeToString(img),
+		"boundingbox":      "1",
+		"textinstructions": instructions,
+	}
+	res, err := c.solveProxyless(ctx, params, delay, retries, opts)
+	if err != nil {
+		return nil, res.ID, err
+	}
+	boxes, err := ParseBoundingBoxes(res.Answer)
+	return boxes, res.ID, err
+}
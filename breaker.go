@@ -0,0 +1,82 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// ErrProviderUnavailable is returned by the submissions of a client created
+// with WithCircuitBreaker while the API is considered unavailable
+var ErrProviderUnavailable = errors.New("2captcha is unavailable, submission skipped")
+
+// breaker is a circuit breaker of the submissions of a client
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openedAt  time.Time
+	probing   bool
+}
+
+// WithCircuitBreaker stops submitting captchas for cooldown after threshold
+// consecutive submissions failed with a server error, a network error or
+// ERROR_NO_SLOT_AVAILABLE, the submissions fail with ErrProviderUnavailable
+// meanwhile. After the cooldown a single submission is let through, the
+// breaker closes if it succeeds and opens for another cooldown otherwise.
+// It prevents retry storms spending the budget during the outages of 2captcha.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *TwoCaptchaClient) {
+		if threshold > 0 {
+			c.breaker = &breaker{threshold: threshold, cooldown: cooldown}
+		}
+	}
+}
+
+// allow reports whether a submission may be sent
+func (b *breaker) allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return true
+	}
+	if time.Since(b.openedAt) < b.cooldown || b.probing {
+		return false
+	}
+	// half-open, let a single submission probe the API
+	b.probing = true
+	return true
+}
+
+// record records the outcome of a submission let through by allow
+func (b *breaker) record(err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	switch {
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		// the submission was abandoned, the API is not to blame
+	case isTransient(err) || isNetError(err) || errors.Is(err, ErrNoSlotAvailable):
+		b.failures++
+		if b.failures >= b.threshold {
+			b.openedAt = time.Now()
+		}
+	default:
+		b.failures = 0
+	}
+}
+
+// isNetError reports whether err is a network error, e.g. of the JSON API v2
+func isNetError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
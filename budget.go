@@ -0,0 +1,131 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded is returned by the solves of a client with a Budget once
+// the spend of the last hour or day reached its cap. The captcha is not
+// submitted.
+var ErrBudgetExceeded = errors.New("Budget exceeded")
+
+// Budget caps the spend of a client in USD
+type Budget struct {
+	// Hourly is the maximum spend in the last hour, zero is unlimited
+	Hourly float64
+	// Daily is the maximum spend in the last 24 hours, zero is unlimited
+	Daily float64
+	// BalanceCheck is the interval of the getbalance checks counting the
+	// spend not reported with the solved captchas, e.g. in TextFormat or by
+	// other clients of the account. Zero disables the checks.
+	BalanceCheck time.Duration
+}
+
+// spend is a cost recorded by the budget
+type spend struct {
+	at   time.Time
+	cost float64
+}
+
+// budget tracks the spend of a client
+type budget struct {
+	Budget
+
+	mu     sync.Mutex
+	spends []spend
+	// balance is the balance of the last successful check, known is false
+	// before it; checked is the time of the last check
+	balance float64
+	known   bool
+	checked time.Time
+	// reported is the spend recorded since the last check
+	reported float64
+}
+
+// WithBudget makes the client refuse new submissions with ErrBudgetExceeded
+// once the spend of the last hour or day reaches the caps of b. The spend
+// is the cost of the solved captchas, reported by the API in JSONFormat,
+// plus the balance decrease measured by the BalanceCheck.
+func WithBudget(b Budget) Option {
+	return func(c *TwoCaptchaClient) {
+		c.budget = &budget{Budget: b}
+	}
+}
+
+// checkBudget returns ErrBudgetExceeded if the budget of the client is spent
+func (c *TwoCaptchaClient) checkBudget(ctx context.Context) error {
+	b := c.budget
+	if b == nil || c.DryRun {
+		return nil
+	}
+	b.mu.Lock()
+	due := b.BalanceCheck > 0 && time.Since(b.checked) >= b.BalanceCheck
+	if due {
+		// only one of the concurrent submissions checks the balance
+		b.checked = time.Now()
+	}
+	b.mu.Unlock()
+
+	if due {
+		balance, err := c.GetBalanceWithContext(ctx)
+		if err != nil {
+			c.logf("2captcha: budget balance check failed: %v", err)
+		} else {
+			b.checkBalance(balance)
+		}
+	}
+	if b.exceeded() {
+		return ErrBudgetExceeded
+	}
+	return nil
+}
+
+// spend records the cost of a solved captcha
+func (b *budget) spend(cost float64) {
+	if cost <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.spends = append(b.spends, spend{at: time.Now(), cost: cost})
+	b.reported += cost
+}
+
+// checkBalance records the balance decrease not reported since the last check
+func (b *budget) checkBalance(balance float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.known {
+		if unreported := b.balance - balance - b.reported; unreported > 0 {
+			b.spends = append(b.spends, spend{at: time.Now(), cost: unreported})
+		}
+	}
+	b.balance = balance
+	b.known = true
+	b.reported = 0
+}
+
+// exceeded reports whether the spend reached a cap of the budget
+func (b *budget) exceeded() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	// drop the spends older than a day
+	i := 0
+	for i < len(b.spends) && now.Sub(b.spends[i].at) >= 24*time.Hour {
+		i++
+	}
+	b.spends = b.spends[i:]
+
+	var hourly, daily float64
+	for _, s := range b.spends {
+		daily += s.cost
+		if now.Sub(s.at) < time.Hour {
+			hourly += s.cost
+		}
+	}
+	return (b.Hourly > 0 && hourly >= b.Hourly) || (b.Daily > 0 && daily >= b.Daily)
+}
@@ -0,0 +1,119 @@
+package twocaptcha
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+)
+
+// AnswerCache stores the answers of the solved image captchas, see
+// WithImageCache. The methods are called concurrently by the solves.
+type AnswerCache interface {
+	// Get returns the answer stored under key if it did not expire
+	Get(key string) (CaptchaResult, bool)
+	// Set stores an answer under key for ttl
+	Set(key string, res CaptchaResult, ttl time.Duration)
+}
+
+// WithImageCache answers the image captchas seen within ttl from cache
+// instead of paying for them again, e.g. for the sites reusing a few
+// captcha images. The captchas are identified by the hash of the image, the
+// ImageOptions and the parameters of the solve options, e.g. WithLang. The cached results have Cached set, they must not be
+// reported to 2captcha again.
+func WithImageCache(cache AnswerCache, ttl time.Duration) Option {
+	return func(c *TwoCaptchaClient) {
+		c.cache = cache
+		c.cacheTTL = ttl
+	}
+}
+
+// cacheKey returns the cache key of an image captcha
+func cacheKey(image []byte, params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	h.Write(image)
+	for _, k := range keys {
+		h.Write([]byte{0})
+		h.Write([]byte(k + "=" + params[k]))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// solveCached solves an image captcha with solve unless its answer is cached.
+// The parameters of the solve options and of the client defaults are part of
+// the key, e.g. an answer cached for a language is not used for another.
+func (c *TwoCaptchaClient) solveCached(image []byte, params map[string]string, solveOpts []SolveOption, solve func() (CaptchaResult, error)) (CaptchaResult, error) {
+	if c.cache == nil {
+		return solve()
+	}
+	key := cacheKey(image, c.clientProfile().params(c.solveOptions(solveOpts).params(params)))
+	if res, ok := c.cache.Get(key); ok {
+		res.Cached = true
+		return res, nil
+	}
+	res, err := solve()
+	if err == nil {
+		c.cache.Set(key, res, c.cacheTTL)
+	}
+	return res, err
+}
+
+// MemoryCache is an in-memory AnswerCache
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	// sweep is the size of entries triggering the removal of the expired ones
+	sweep int
+}
+
+type cacheEntry struct {
+	res     CaptchaResult
+	expires time.Time
+}
+
+// minCacheSweep is the smallest size of a MemoryCache removing expired entries
+const minCacheSweep = 64
+
+// NewMemoryCache creates an empty MemoryCache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]cacheEntry), sweep: minCacheSweep}
+}
+
+// Get returns the answer stored under key if it did not expire
+func (m *MemoryCache) Get(key string) (CaptchaResult, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok {
+		return CaptchaResult{}, false
+	}
+	if time.Now().After(e.expires) {
+		delete(m.entries, key)
+		return CaptchaResult{}, false
+	}
+	return e.res, true
+}
+
+// Set stores an answer under key for ttl
+func (m *MemoryCache) Set(key string, res CaptchaResult, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	if len(m.entries) >= m.sweep {
+		for k, e := range m.entries {
+			if now.After(e.expires) {
+				delete(m.entries, k)
+			}
+		}
+		if m.sweep = 2 * len(m.entries); m.sweep < minCacheSweep {
+			m.sweep = minCacheSweep
+		}
+	}
+	m.entries[key] = cacheEntry{res: res, expires: now.Add(ttl)}
+}
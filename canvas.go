@@ -0,0 +1,46 @@
+package twocaptcha
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"time"
+)
+
+// SolveCanvas performs a canvas captcha solving request to 2captcha.com and
+// returns with the outline drawn by the worker around the object and captcha
+// ID if the request was successful. instructions tells the worker what to
+// draw around, e.g. "draw around the apple". An example of the object can
+// be attached with WithInstructionImage.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#canvas
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type DrawAroundTask.
+func (c *TwoCaptchaClient) SolveCanvas(image []byte, instructions string, delay time.Duration, retries int, opts ...SolveOption) (Polygon, string, error) {
+	return c.SolveCanvasWithContext(context.Background(), image, instructions, delay, retries, opts...)
+}
+
+// SolveCanvasWithContext is SolveCanvas with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type DrawAroundTask.
+func (c *TwoCaptchaClient) SolveCanvasWithContext(ctx context.Context, image []byte, instructions string, delay time.Duration, retries int, opts ...SolveOption) (Polygon, string, error) {
+	if instructions == "" {
+		return nil, "", errors.New("Instructions are required for canvas captchas")
+	}
+	if err := validateImage(image); err != nil {
+		return nil, "", err
+	}
+	params := map[string]string{
+		"method":           "base64",
+		"body":             base64.StdEncoding.EncodeToString(image),
+		"canvas":           "1",
+		"textinstructions": instructions,
+	}
+	res, err := c.solveProxyless(ctx, params, delay, retries, opts)
+	if err != nil {
+		return nil, res.ID, err
+	}
+	outline, err := ParsePolygon(res.Answer)
+	return outline, res.ID, err
+}
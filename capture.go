@@ -0,0 +1,76 @@
+package twocaptcha
+
+import (
+	"sync"
+	"time"
+)
+
+// Exchange is an API request and its response captured by WithCapture.
+// The API keys, the proxy credentials and the pingback token are redacted.
+type Exchange struct {
+	// Time is the time the request was sent
+	Time time.Time
+	// Duration is the time until the response was read
+	Duration time.Duration
+	// URL is the URL of the request
+	URL string
+	// Request contains the form values of the request, the JSON body for the
+	// JSON API v2. The uploaded files are not included.
+	Request string
+	// StatusCode is the HTTP status code of the response, zero if the
+	// request failed
+	StatusCode int
+	// Response is the raw body of the response
+	Response []byte
+	// Err is the error of the request if no response was received
+	Err error
+}
+
+// capture is a ring buffer of the last exchanges of a client
+type capture struct {
+	mu        sync.Mutex
+	exchanges []Exchange
+	next      int
+	full      bool
+}
+
+// WithCapture keeps the last n API requests and responses of the client,
+// see Exchanges. They make the bug reports to 2captcha support actionable.
+func WithCapture(n int) Option {
+	return func(c *TwoCaptchaClient) {
+		if n > 0 {
+			c.capture = &capture{exchanges: make([]Exchange, n)}
+		}
+	}
+}
+
+// Exchanges returns the last API requests and responses of the client,
+// oldest first. It returns nil if the client was created without WithCapture.
+func (c *TwoCaptchaClient) Exchanges() []Exchange {
+	if c.capture == nil {
+		return nil
+	}
+	c.capture.mu.Lock()
+	defer c.capture.mu.Unlock()
+	if !c.capture.full {
+		return append([]Exchange(nil), c.capture.exchanges[:c.capture.next]...)
+	}
+	res := append([]Exchange(nil), c.capture.exchanges[c.capture.next:]...)
+	return append(res, c.capture.exchanges[:c.capture.next]...)
+}
+
+// record captures an exchange if the client captures them
+func (c *TwoCaptchaClient) record(e Exchange) {
+	if c.capture == nil {
+		return
+	}
+	e.Duration = time.Since(e.Time)
+	c.capture.mu.Lock()
+	defer c.capture.mu.Unlock()
+	c.capture.exchanges[c.capture.next] = e
+	c.capture.next++
+	if c.capture.next == len(c.capture.exchanges) {
+		c.capture.next = 0
+		c.capture.full = true
+	}
+}
@@ -0,0 +1,49 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// CapyResult is a solved Capy Puzzle captcha
+type CapyResult struct {
+	CaptchaKey   string `json:"captchakey"`
+	ChallengeKey string `json:"challengekey"`
+	Answer       string `json:"answer"`
+}
+
+// SolveCapy performs a Capy Puzzle captcha solving request to 2captcha.com
+// and returns with the solved captcha and captcha ID if the request was successful.
+// apiServer is the domain of the Capy script on the page, it can be empty.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#solving_capy
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type CapyTaskProxyless.
+func (c *TwoCaptchaClient) SolveCapy(siteKey, siteURL, apiServer string, delay time.Duration, retries int, opts ...SolveOption) (CapyResult, string, error) {
+	return c.SolveCapyWithContext(context.Background(), siteKey, siteURL, apiServer, delay, retries, opts...)
+}
+
+// SolveCapyWithContext is SolveCapy with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type CapyTaskProxyless.
+func (c *TwoCaptchaClient) SolveCapyWithContext(ctx context.Context, siteKey, siteURL, apiServer string, delay time.Duration, retries int, opts ...SolveOption) (CapyResult, string, error) {
+	params := map[string]string{
+		"captchakey": siteKey,
+		"pageurl":    siteURL,
+		"method":     "capy",
+	}
+	if apiServer != "" {
+		params["api_server"] = apiServer
+	}
+	res, err := c.solve(ctx, params, delay, retries, opts)
+	if err != nil {
+		return CapyResult{}, res.ID, err
+	}
+	var result CapyResult
+	if err := parseObjectAnswer(res.Answer, &result); err != nil {
+		return result, res.ID, errors.New("Invalid Capy answer: " + res.Answer)
+	}
+	return result, res.ID, nil
+}
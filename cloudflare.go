@@ -0,0 +1,64 @@
+package twocaptcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// CloudflareChallenge contains the parameters of the Turnstile widget of a
+// Cloudflare Challenge page. They are passed to turnstile.render by the
+// scripts of the page and are not part of its HTML, capture them in the
+// browser with inject.TurnstileInterceptor and inject.TurnstileParams.
+type CloudflareChallenge struct {
+	SiteKey   string `json:"sitekey"`
+	Action    string `json:"action"`
+	CData     string `json:"cData"`
+	PageData  string `json:"chlPageData"`
+	UserAgent string `json:"userAgent"`
+}
+
+// IsCloudflareChallenge reports whether resp is a Cloudflare Challenge page
+func IsCloudflareChallenge(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusServiceUnavailable {
+		return false
+	}
+	return resp.Header.Get("Cf-Mitigated") == "challenge"
+}
+
+// ParseCloudflareChallenge parses the parameters captured by the script of
+// inject.TurnstileParams
+func ParseCloudflareChallenge(data []byte) (CloudflareChallenge, error) {
+	var ch CloudflareChallenge
+	if err := json.Unmarshal(data, &ch); err != nil {
+		return ch, err
+	}
+	if ch.SiteKey == "" {
+		return ch, errors.New("Turnstile site key not captured")
+	}
+	return ch, nil
+}
+
+// SolveCloudflareChallenge solves the Turnstile widget of the Cloudflare
+// Challenge page pageURL. The token is passed to the page with
+// inject.TurnstileCallback, Cloudflare then sets the cf_clearance cookie in
+// the browser. The cookie is bound to the user agent of the challenge and
+// the IP address of the browser, replay it with the same user agent, and
+// solve the challenge with WithProxy set to the proxy of the browser.
+func (c *TwoCaptchaClient) SolveCloudflareChallenge(ctx context.Context, pageURL string, ch CloudflareChallenge, opts ...SolveOption) (CaptchaResult, error) {
+	if ch.PageData == "" {
+		return CaptchaResult{}, errors.New("chlPageData is required for Cloudflare Challenge pages")
+	}
+	params, err := turnstileParams(pageURL, ch.SiteKey, TurnstileOptions{
+		Action:    ch.Action,
+		Data:      ch.CData,
+		PageData:  ch.PageData,
+		UserAgent: ch.UserAgent,
+	})
+	if err != nil {
+		return CaptchaResult{}, err
+	}
+	delay, retries := c.polling()
+	return c.solve(ctx, params, delay, retries, opts)
+}
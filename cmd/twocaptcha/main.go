@@ -0,0 +1,191 @@
+// Command twocaptcha solves captchas with 2captcha.com from the command line.
+//
+// Usage:
+//
+//	twocaptcha [flags] balance
+//	twocaptcha [flags] solve recaptcha-v2 -url URL -sitekey KEY
+//	twocaptcha [flags] solve recaptcha-v3 -url URL -sitekey KEY [-action ACTION] [-min-score SCORE]
+//	twocaptcha [flags] solve hcaptcha -url URL -sitekey KEY
+//	twocaptcha [flags] solve turnstile -url URL -sitekey KEY
+//	twocaptcha [flags] solve image FILE
+//	twocaptcha [flags] solve text [-lang LANG] QUESTION
+//	twocaptcha [flags] report-good ID
+//	twocaptcha [flags] report-bad ID
+//
+// The flags of the client precede the command, the flags of a solve may
+// follow its arguments, e.g. solve text QUESTION -lang en.
+// The API key is read from the -key flag or the TWOCAPTCHA_API_KEY
+// environment variable. The answer of a solve is printed to the standard
+// output, the captcha ID to the standard error.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/gocolly/twocaptcha"
+)
+
+const usage = `Usage:
+  twocaptcha [flags] balance
+  twocaptcha [flags] solve recaptcha-v2 -url URL -sitekey KEY
+  twocaptcha [flags] solve recaptcha-v3 -url URL -sitekey KEY [-action ACTION] [-min-score SCORE]
+  twocaptcha [flags] solve hcaptcha -url URL -sitekey KEY
+  twocaptcha [flags] solve turnstile -url URL -sitekey KEY
+  twocaptcha [flags] solve image FILE
+  twocaptcha [flags] solve text [-lang LANG] QUESTION
+  twocaptcha [flags] report-good ID
+  twocaptcha [flags] report-bad ID
+
+The flags of the client precede the command, the flags of a solve may
+follow its arguments.
+
+Flags:
+`
+
+func main() {
+	key := flag.String("key", os.Getenv("TWOCAPTCHA_API_KEY"), "2captcha API key, defaults to $TWOCAPTCHA_API_KEY")
+	baseURL := flag.String("base-url", "", "base URL of the API, e.g. https://rucaptcha.com")
+	timeout := flag.Duration("timeout", 5*time.Minute, "maximum duration of a solve")
+	debug := flag.Bool("debug", false, "log the API requests and responses to the standard error")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	opts := []twocaptcha.Option{twocaptcha.WithMaxWait(*timeout)}
+	if *baseURL != "" {
+		opts = append(opts, twocaptcha.WithBaseURL(*baseURL))
+	}
+	c := twocaptcha.New(*key, opts...)
+	if *debug {
+		c.Logger = log.New(os.Stderr, "", log.LstdFlags)
+		c.Debug = true
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	go func() {
+		<-signals
+		cancel()
+	}()
+
+	if err := run(ctx, c, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "twocaptcha:", err)
+		os.Exit(1)
+	}
+}
+
+// run executes a command
+func run(ctx context.Context, c *twocaptcha.TwoCaptchaClient, args []string) error {
+	switch args[0] {
+	case "balance":
+		balance, err := c.GetBalanceWithContext(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Println(strconv.FormatFloat(balance, 'f', -1, 64))
+		return nil
+	case "report-good", "report-bad":
+		if len(args) != 2 {
+			return fmt.Errorf("%s requires a captcha ID", args[0])
+		}
+		if args[0] == "report-good" {
+			return c.ReportGoodCaptchaWithContext(ctx, args[1])
+		}
+		return c.ReportBadCaptchaWithContext(ctx, args[1])
+	case "solve":
+		if len(args) < 2 {
+			return fmt.Errorf("solve requires a captcha type")
+		}
+		answer, id, err := solve(ctx, c, args[1], args[2:])
+		if id != "" {
+			fmt.Fprintln(os.Stderr, "captcha id:", id)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println(answer)
+		return nil
+	}
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+// solve solves a captcha of type typ and returns its answer and ID
+func solve(ctx context.Context, c *twocaptcha.TwoCaptchaClient, typ string, args []string) (string, string, error) {
+	const delay, retries = 5, 60
+
+	fs := flag.NewFlagSet("solve "+typ, flag.ExitOnError)
+	siteURL := fs.String("url", "", "URL of the page with the captcha")
+	siteKey := fs.String("sitekey", "", "site key of the captcha")
+	action := fs.String("action", "", "reCAPTCHA v3 action")
+	minScore := fs.Float64("min-score", 0.3, "reCAPTCHA v3 minimum score")
+	lang := fs.String("lang", "", "language of the text captcha, e.g. en")
+	args = parseFlags(fs, args)
+
+	switch typ {
+	case "recaptcha-v2", "recaptcha-v3", "hcaptcha", "turnstile":
+		if *siteURL == "" || *siteKey == "" {
+			return "", "", fmt.Errorf("solve %s requires -url and -sitekey", typ)
+		}
+	case "image", "text":
+		if len(args) != 1 {
+			return "", "", fmt.Errorf("solve %s requires an argument", typ)
+		}
+	}
+
+	switch typ {
+	case "recaptcha-v2":
+		return c.SolveRecaptchaWithContext(ctx, *siteURL, *siteKey, twocaptcha.RecaptchaOptions{}, delay, retries)
+	case "recaptcha-v3":
+		res, err := c.SolveRecaptchaV3Result(ctx, *siteURL, *siteKey, *action, *minScore)
+		return res.Answer, res.ID, err
+	case "hcaptcha":
+		return c.SolveHCaptchaWithContext(ctx, *siteURL, *siteKey, twocaptcha.HCaptchaOptions{}, delay, retries)
+	case "turnstile":
+		return c.SolveTurnstileWithContext(ctx, *siteURL, *siteKey, twocaptcha.TurnstileOptions{}, delay, retries)
+	case "image":
+		image, err := ioutil.ReadFile(args[0])
+		if err != nil {
+			return "", "", err
+		}
+		res, err := c.SolveImageCaptcha(ctx, image, twocaptcha.ImageOptions{})
+		return res.Answer, res.ID, err
+	case "text":
+		res, err := c.SolveTextCaptcha(ctx, args[0], *lang)
+		return res.Answer, res.ID, err
+	}
+	return "", "", fmt.Errorf("unknown captcha type %q", typ)
+}
+
+// parseFlags parses the flags of fs placed anywhere in args and returns the
+// other arguments. The arguments after "--" are never parsed as flags.
+func parseFlags(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		consumed := len(args) - fs.NArg()
+		if consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, fs.Args()...)
+		}
+		if fs.NArg() == 0 {
+			return positional
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
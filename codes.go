@@ -0,0 +1,116 @@
+package twocaptcha
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Error codes returned by the API in APIError.Code.
+// See more details on https://2captcha.com/2captcha-api#error_handling
+const (
+	// in.php
+	CodeWrongUserKey          = "ERROR_WRONG_USER_KEY"
+	CodeKeyDoesNotExist       = "ERROR_KEY_DOES_NOT_EXIST"
+	CodeZeroBalance           = "ERROR_ZERO_BALANCE"
+	CodePageURL               = "ERROR_PAGEURL"
+	CodeNoSlotAvailable       = "ERROR_NO_SLOT_AVAILABLE"
+	CodeZeroCaptchaFilesize   = "ERROR_ZERO_CAPTCHA_FILESIZE"
+	CodeTooBigCaptchaFilesize = "ERROR_TOO_BIG_CAPTCHA_FILESIZE"
+	CodeWrongFileExtension    = "ERROR_WRONG_FILE_EXTENSION"
+	CodeImageTypeNotSupported = "ERROR_IMAGE_TYPE_NOT_SUPPORTED"
+	CodeUpload                = "ERROR_UPLOAD"
+	CodeIPNotAllowed          = "ERROR_IP_NOT_ALLOWED"
+	CodeIPBanned              = "IP_BANNED"
+	CodeErrorIPBanned         = "ERROR_IP_BANNED"
+	CodeBadTokenOrPageURL     = "ERROR_BAD_TOKEN_OR_PAGEURL"
+	CodeGoogleKey             = "ERROR_GOOGLEKEY"
+	CodeWrongGoogleKey        = "ERROR_WRONG_GOOGLEKEY"
+	CodeCaptchaImageBlocked   = "ERROR_CAPTCHAIMAGE_BLOCKED"
+	CodeTooManyBadImages      = "TOO_MANY_BAD_IMAGES"
+	CodeMaxUserTurn           = "MAX_USER_TURN"
+	CodeBadParameters         = "ERROR_BAD_PARAMETERS"
+	CodeBadProxy              = "ERROR_BAD_PROXY"
+	CodeTooMuchRequests       = "ERROR_TOO_MUCH_REQUESTS"
+	CodeProxyConnectionFailed = "ERROR_PROXY_CONNECTION_FAILED"
+	CodeEmptyAction           = "ERROR_EMPTY_ACTION"
+	CodeMethodCallNotAllowed  = "ERROR_METHOD_CALL"
+	CodeWrongAction           = "ERROR_WRONG_ACTION"
+	CodeCaptchaIDMissing      = "ERROR_CAPTCHA_ID"
+	CodeInternalServerError   = "ERROR_INTERNAL_SERVER_ERROR"
+	CodeNotReady              = "CAPCHA_NOT_READY"
+	CodeCaptchaUnsolvable     = "ERROR_CAPTCHA_UNSOLVABLE"
+	CodeWrongIDFormat         = "ERROR_WRONG_ID_FORMAT"
+	CodeWrongCaptchaID        = "ERROR_WRONG_CAPTCHA_ID"
+	CodeBadDuplicates         = "ERROR_BAD_DUPLICATES"
+	CodeReportNotRecorded     = "ERROR_REPORT_NOT_RECORDED"
+	CodeDuplicateReport       = "ERROR_DUPLICATE_REPORT"
+	CodeIPAddress             = "ERROR_IP_ADDRES"
+	CodeTokenExpired          = "ERROR_TOKEN_EXPIRED"
+
+	// JSON API v2
+	CodeIPBlocked = "ERROR_IP_BLOCKED"
+)
+
+// retryableCodes are the error codes of the requests which may succeed if
+// they are repeated later without changes
+var retryableCodes = map[string]bool{
+	CodeNoSlotAvailable:     true,
+	CodeMaxUserTurn:         true,
+	CodeTooMuchRequests:     true,
+	CodeInternalServerError: true,
+	CodeNotReady:            true,
+}
+
+// IsRetryable reports whether the request failing with err may succeed if it
+// is repeated later without changes, e.g. after ERROR_NO_SLOT_AVAILABLE or a
+// network error. The captcha must be changed or resubmitted after the other
+// errors, e.g. ERROR_CAPTCHA_UNSOLVABLE.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var transient *transientError
+	if errors.As(err, &transient) || errors.Is(err, ErrNotReady) {
+		return true
+	}
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && retryableCodes[apiErr.Code]
+}
+
+// ParseError returns the *APIError of a raw API response in any format: the
+// text format, e.g. ERROR_ZERO_BALANCE, the JSON format of in.php and res.php
+// or the JSON API v2 format. nil is returned if the response is not an error.
+func ParseError(body []byte) *APIError {
+	s := strings.TrimSpace(string(body))
+	if strings.HasPrefix(s, "{") {
+		var r struct {
+			Status           *int            `json:"status"`
+			Request          json.RawMessage `json:"request"`
+			ErrorText        string          `json:"error_text"`
+			ErrorID          int             `json:"errorId"`
+			ErrorCode        string          `json:"errorCode"`
+			ErrorDescription string          `json:"errorDescription"`
+		}
+		if err := json.Unmarshal([]byte(s), &r); err != nil {
+			return nil
+		}
+		if r.ErrorID != 0 {
+			return &APIError{Code: r.ErrorCode, Description: r.ErrorDescription}
+		}
+		var code string
+		if r.Status != nil && *r.Status != 1 && json.Unmarshal(r.Request, &code) == nil && code != CodeNotReady {
+			return &APIError{Code: code, Description: r.ErrorText}
+		}
+		return nil
+	}
+	code := s
+	description := ""
+	if i := strings.Index(s, "|"); i >= 0 {
+		code, description = s[:i], s[i+1:]
+	}
+	if strings.HasPrefix(code, "ERROR") || code == CodeIPBanned || code == CodeMaxUserTurn || code == CodeTooManyBadImages {
+		return &APIError{Code: code, Description: description}
+	}
+	return nil
+}
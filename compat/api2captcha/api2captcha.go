@@ -0,0 +1,262 @@
+// Package api2captcha adapts the twocaptcha client to the API shape of the
+// official 2captcha-go SDK, so the projects using the SDK can switch to
+// twocaptcha by changing the import path:
+//
+//	client := api2captcha.NewClient("API_KEY")
+//	cap := api2captcha.ReCaptcha{SiteKey: "SITE_KEY", Url: "https://example.com"}
+//	code, captchaId, err := client.Solve(cap.ToRequest())
+//
+// The solves go through twocaptcha.SolveCustom, the features of twocaptcha
+// are used through the Client field. The captcha types of the SDK not
+// covered here are sent with a Request of their in.php parameters.
+package api2captcha
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"strconv"
+	"time"
+
+	"github.com/gocolly/twocaptcha"
+)
+
+// Request is a captcha solving request: the in.php parameters and the files
+// sent with them by form field name
+type Request struct {
+	Params map[string]string
+	// Files are the paths of the files by field name, e.g. "file". They are
+	// sent base64 encoded.
+	Files map[string]string
+}
+
+// Client is the client of the SDK
+type Client struct {
+	// Client is the twocaptcha client solving the captchas
+	Client *twocaptcha.TwoCaptchaClient
+	// DefaultTimeout is the timeout of a solve in seconds
+	DefaultTimeout int
+	// RecaptchaTimeout is the timeout of the reCAPTCHA solves in seconds
+	RecaptchaTimeout int
+	// PollingInterval is the interval of the polls in seconds
+	PollingInterval int
+}
+
+// NewClient creates a Client with apiKey
+func NewClient(apiKey string) *Client {
+	return &Client{
+		Client:           twocaptcha.New(apiKey),
+		DefaultTimeout:   120,
+		RecaptchaTimeout: 600,
+		PollingInterval:  10,
+	}
+}
+
+// Solve solves a captcha and returns with its answer and captcha ID
+func (c *Client) Solve(req Request) (string, string, error) {
+	params := make(map[string]string, len(req.Params)+1)
+	for k, v := range req.Params {
+		params[k] = v
+	}
+	for field, path := range req.Files {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", "", err
+		}
+		if field == "file" {
+			field = "body"
+			params["method"] = "base64"
+		}
+		params[field] = base64.StdEncoding.EncodeToString(data)
+	}
+	method := params["method"]
+	if method == "" {
+		return "", "", errors.New("Method is required")
+	}
+	delete(params, "method")
+
+	timeout := c.DefaultTimeout
+	if method == "userrecaptcha" {
+		timeout = c.RecaptchaTimeout
+	}
+	opts := []twocaptcha.SolveOption{
+		twocaptcha.WithTimeout(time.Duration(timeout) * time.Second),
+		twocaptcha.WithPollInterval(time.Duration(c.PollingInterval) * time.Second),
+	}
+	res, err := c.Client.SolveCustom(context.Background(), method, params, opts...)
+	return res.Answer, res.ID, err
+}
+
+// GetBalance returns the balance of the account in USD
+func (c *Client) GetBalance() (float64, error) {
+	return c.Client.GetBalance()
+}
+
+// Report reports a solved captcha as correct or incorrect
+func (c *Client) Report(id string, correct bool) error {
+	if correct {
+		return c.Client.ReportGoodCaptcha(id)
+	}
+	return c.Client.ReportBadCaptcha(id)
+}
+
+// Normal is an image captcha, File is the path of the image or Base64 the
+// base64 encoded image
+type Normal struct {
+	File          string
+	Base64        string
+	Phrase        bool
+	CaseSensitive bool
+	Calc          bool
+	Numeric       int
+	MinLen        int
+	MaxLen        int
+	Lang          string
+	HintText      string
+}
+
+// ToRequest converts the captcha to a Request
+func (n *Normal) ToRequest() Request {
+	req := Request{Params: map[string]string{"method": "post"}}
+	if n.File != "" {
+		req.Files = map[string]string{"file": n.File}
+	} else {
+		req.Params["method"] = "base64"
+		req.Params["body"] = n.Base64
+	}
+	setBool(req.Params, "phrase", n.Phrase)
+	setBool(req.Params, "regsense", n.CaseSensitive)
+	setBool(req.Params, "calc", n.Calc)
+	setInt(req.Params, "numeric", n.Numeric)
+	setInt(req.Params, "min_len", n.MinLen)
+	setInt(req.Params, "max_len", n.MaxLen)
+	setString(req.Params, "lang", n.Lang)
+	setString(req.Params, "textinstructions", n.HintText)
+	return req
+}
+
+// Text is a text captcha, a question answered by the worker
+type Text struct {
+	Text string
+	Lang string
+}
+
+// ToRequest converts the captcha to a Request
+func (t *Text) ToRequest() Request {
+	req := Request{Params: map[string]string{"method": "post", "textcaptcha": t.Text}}
+	setString(req.Params, "lang", t.Lang)
+	return req
+}
+
+// ReCaptcha is a reCAPTCHA v2 or v3, Version is "v2" by default
+type ReCaptcha struct {
+	SiteKey    string
+	Url        string
+	Invisible  bool
+	Version    string
+	Enterprise bool
+	Action     string
+	Score      float64
+	DataS      string
+	UserAgent  string
+}
+
+// ToRequest converts the captcha to a Request
+func (r *ReCaptcha) ToRequest() Request {
+	req := Request{Params: map[string]string{"method": "userrecaptcha", "googlekey": r.SiteKey, "pageurl": r.Url}}
+	setBool(req.Params, "invisible", r.Invisible)
+	setString(req.Params, "version", r.Version)
+	setBool(req.Params, "enterprise", r.Enterprise)
+	setString(req.Params, "action", r.Action)
+	if r.Score > 0 {
+		req.Params["min_score"] = strconv.FormatFloat(r.Score, 'f', -1, 64)
+	}
+	setString(req.Params, "data-s", r.DataS)
+	setString(req.Params, "userAgent", r.UserAgent)
+	return req
+}
+
+// HCaptcha is an hCaptcha
+type HCaptcha struct {
+	SiteKey string
+	Url     string
+}
+
+// ToRequest converts the captcha to a Request
+func (h *HCaptcha) ToRequest() Request {
+	return Request{Params: map[string]string{"method": "hcaptcha", "sitekey": h.SiteKey, "pageurl": h.Url}}
+}
+
+// CloudflareTurnstile is a Cloudflare Turnstile captcha
+type CloudflareTurnstile struct {
+	SiteKey   string
+	Url       string
+	Action    string
+	Data      string
+	PageData  string
+	UserAgent string
+}
+
+// ToRequest converts the captcha to a Request
+func (t *CloudflareTurnstile) ToRequest() Request {
+	req := Request{Params: map[string]string{"method": "turnstile", "sitekey": t.SiteKey, "pageurl": t.Url}}
+	setString(req.Params, "action", t.Action)
+	setString(req.Params, "data", t.Data)
+	setString(req.Params, "pagedata", t.PageData)
+	setString(req.Params, "userAgent", t.UserAgent)
+	return req
+}
+
+// FunCaptcha is an Arkose Labs FunCaptcha
+type FunCaptcha struct {
+	SiteKey   string
+	Url       string
+	Surl      string
+	UserAgent string
+	Data      map[string]string
+}
+
+// ToRequest converts the captcha to a Request
+func (f *FunCaptcha) ToRequest() Request {
+	req := Request{Params: map[string]string{"method": "funcaptcha", "publickey": f.SiteKey, "pageurl": f.Url}}
+	setString(req.Params, "surl", f.Surl)
+	setString(req.Params, "userAgent", f.UserAgent)
+	for k, v := range f.Data {
+		req.Params["data["+k+"]"] = v
+	}
+	return req
+}
+
+// GeeTest is a GeeTest v3 captcha
+type GeeTest struct {
+	GT        string
+	Challenge string
+	Url       string
+	ApiServer string
+}
+
+// ToRequest converts the captcha to a Request
+func (g *GeeTest) ToRequest() Request {
+	req := Request{Params: map[string]string{"method": "geetest", "gt": g.GT, "challenge": g.Challenge, "pageurl": g.Url}}
+	setString(req.Params, "api_server", g.ApiServer)
+	return req
+}
+
+func setString(params map[string]string, key, value string) {
+	if value != "" {
+		params[key] = value
+	}
+}
+
+func setBool(params map[string]string, key string, value bool) {
+	if value {
+		params[key] = "1"
+	}
+}
+
+func setInt(params map[string]string, key string, value int) {
+	if value != 0 {
+		params[key] = strconv.Itoa(value)
+	}
+}
@@ -0,0 +1,199 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gocolly/twocaptcha/twocaptchatest"
+)
+
+// parallelism is the number of goroutines sharing a client in the tests
+const parallelism = 50
+
+// newTestClient creates a client sending its requests to s and polling
+// every millisecond
+func newTestClient(s *twocaptchatest.Server, opts ...Option) *TwoCaptchaClient {
+	c := New("KEY", append([]Option{WithHTTPClient(s.Client()), WithMinPollInterval(0)}, opts...)...)
+	c.Backoff = ConstantBackoff(time.Millisecond)
+	return c
+}
+
+// solveParallel solves n reCAPTCHAs concurrently with c and returns their
+// results and errors
+func solveParallel(c *TwoCaptchaClient, n int, opts ...SolveOption) ([]CaptchaResult, []error) {
+	results := make([]CaptchaResult, n)
+	errs := make([]error, n)
+	opts = append([]SolveOption{WithInitialWait(0)}, opts...)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = c.SolveCustom(context.Background(), "userrecaptcha", testRecaptchaParams, opts...)
+		}(i)
+	}
+	wg.Wait()
+	return results, errs
+}
+
+func TestConcurrentSpend(t *testing.T) {
+	s := twocaptchatest.NewServer()
+	defer s.Close()
+	s.Price = 0.003
+	c := newTestClient(s)
+
+	results, errs := solveParallel(c, parallelism, WithTag("shop", "a"))
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("solve %d: %v", i, err)
+		}
+		if results[i].Answer != "TOKEN" {
+			t.Errorf("solve %d: answer = %q, want TOKEN", i, results[i].Answer)
+		}
+	}
+	if n := c.SolveCount(); n != parallelism {
+		t.Errorf("SolveCount() = %d, want %d", n, parallelism)
+	}
+	want := parallelism * s.Price
+	if total := c.TotalSpend(); total < want-1e-9 || total > want+1e-9 {
+		t.Errorf("TotalSpend() = %v, want %v", total, want)
+	}
+	if spend := c.TotalSpendByTag("shop")["a"]; spend < want-1e-9 || spend > want+1e-9 {
+		t.Errorf("TotalSpendByTag() = %v, want %v", spend, want)
+	}
+}
+
+func TestConcurrentRateLimit(t *testing.T) {
+	s := twocaptchatest.NewServer()
+	defer s.Close()
+	// bursts of 20 submissions, then 20 per second
+	c := newTestClient(s, WithRateLimit(20, 0))
+
+	start := time.Now()
+	_, errs := solveParallel(c, 30)
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("solve %d: %v", i, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 450*time.Millisecond {
+		t.Errorf("30 submissions took %v, want at least 500ms at 20 per second", elapsed)
+	}
+}
+
+func TestConcurrentKeyPool(t *testing.T) {
+	s := twocaptchatest.NewServer()
+	defer s.Close()
+	s.KeyErrors = map[string]string{"EMPTY": CodeZeroBalance}
+	c := newTestClient(s, WithAPIKeys("EMPTY", "FULL"))
+
+	results, errs := solveParallel(c, parallelism)
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("solve %d: %v", i, err)
+		}
+		if results[i].APIKey != "FULL" {
+			t.Errorf("solve %d: APIKey = %q, want FULL", i, results[i].APIKey)
+		}
+	}
+	// the failed key is skipped once any solve noticed it
+	if _, errs := solveParallel(c, 1); errs[0] != nil {
+		t.Fatal(errs[0])
+	}
+	last := s.Requests()
+	submits := 0
+	for _, r := range last {
+		if r.Get("method") != "" && r.Get("key") == "EMPTY" {
+			submits++
+		}
+	}
+	if submits > parallelism {
+		t.Errorf("%d submissions with the failed key, want at most %d", submits, parallelism)
+	}
+	if r := last[len(last)-2]; r.Get("key") != "FULL" {
+		t.Errorf("submission after the failover used %q, want FULL", r.Get("key"))
+	}
+}
+
+func TestConcurrentSlots(t *testing.T) {
+	s := twocaptchatest.NewServer()
+	defer s.Close()
+	s.Polls = 1
+	const slots = 3
+	c := newTestClient(s, WithMaxConcurrent(slots))
+	var inFlight, maxInFlight int32
+	c.OnStatus = func(captchaId string, status Status) {
+		switch status {
+		case StatusSubmitted:
+			n := atomic.AddInt32(&inFlight, 1)
+			for {
+				max := atomic.LoadInt32(&maxInFlight)
+				if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+					break
+				}
+			}
+		case StatusSolved, StatusFailed:
+			atomic.AddInt32(&inFlight, -1)
+		}
+	}
+
+	_, errs := solveParallel(c, 20)
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("solve %d: %v", i, err)
+		}
+	}
+	if max := atomic.LoadInt32(&maxInFlight); max > slots {
+		t.Errorf("%d captchas in flight, want at most %d", max, slots)
+	}
+}
+
+func TestConcurrentBreaker(t *testing.T) {
+	s := twocaptchatest.NewServer()
+	defer s.Close()
+	s.SubmitError = CodeNoSlotAvailable
+	c := newTestClient(s, WithCircuitBreaker(3, time.Hour))
+
+	_, errs := solveParallel(c, parallelism)
+	for i, err := range errs {
+		if !errors.Is(err, ErrNoSlotAvailable) && !errors.Is(err, ErrProviderUnavailable) {
+			t.Errorf("solve %d: error = %v, want ErrNoSlotAvailable or ErrProviderUnavailable", i, err)
+		}
+	}
+	sent := len(s.Requests())
+	_, errs = solveParallel(c, parallelism)
+	for i, err := range errs {
+		if !errors.Is(err, ErrProviderUnavailable) {
+			t.Errorf("solve %d after the breaker opened: error = %v, want ErrProviderUnavailable", i, err)
+		}
+	}
+	if n := len(s.Requests()); n != sent {
+		t.Errorf("%d requests sent after the breaker opened, want 0", n-sent)
+	}
+}
+
+// TestBreakerRecord checks the abandoned submissions are not counted as
+// failures and the server errors are
+func TestBreakerRecord(t *testing.T) {
+	tests := []struct {
+		err  error
+		open bool
+	}{
+		{&transientError{context.Canceled}, false},
+		{&transientError{context.DeadlineExceeded}, false},
+		{&transientError{errors.New("Server error: 502 Bad Gateway")}, true},
+		{&APIError{Code: CodeNoSlotAvailable}, true},
+	}
+	for _, tt := range tests {
+		b := &breaker{threshold: 2, cooldown: time.Hour}
+		b.record(tt.err)
+		b.record(tt.err)
+		if got := !b.allow(); got != tt.open {
+			t.Errorf("breaker open = %v after %v, want %v", got, tt.err, tt.open)
+		}
+	}
+}
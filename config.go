@@ -0,0 +1,156 @@
+package twocaptcha
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// Config contains the settings of a client read by NewFromEnv or LoadConfig
+type Config struct {
+	// APIKey is the API key of the client
+	APIKey string
+	// SoftID is the SoftID of the client
+	SoftID string
+	// BaseURL is set with WithBaseURL if it is not empty
+	BaseURL string
+	// TaskURL is set with WithTaskURL if it is not empty
+	TaskURL string
+	// HTTPTimeout is set with WithHTTPTimeout if it is not zero
+	HTTPTimeout time.Duration
+	// MaxWait is set with WithMaxWait if it is not zero
+	MaxWait time.Duration
+	// PollInterval is set with WithPolling if it is not zero
+	PollInterval time.Duration
+}
+
+// configFile is the JSON format of a configuration file, the durations are
+// strings accepted by time.ParseDuration, e.g. "30s"
+type configFile struct {
+	APIKey       string `json:"api_key"`
+	SoftID       string `json:"soft_id"`
+	BaseURL      string `json:"base_url"`
+	TaskURL      string `json:"task_url"`
+	HTTPTimeout  string `json:"http_timeout"`
+	MaxWait      string `json:"max_wait"`
+	PollInterval string `json:"poll_interval"`
+}
+
+// LoadConfig reads a JSON configuration file, e.g.
+//
+//	{"api_key": "API_KEY", "base_url": "https://rucaptcha.com", "max_wait": "3m"}
+//
+// The keys are api_key, soft_id, base_url, task_url, http_timeout, max_wait
+// and poll_interval, the durations are strings like "30s".
+func LoadConfig(path string) (Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	var f configFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return Config{}, errors.New("Invalid config file " + path + ": " + err.Error())
+	}
+	cfg := Config{APIKey: f.APIKey, SoftID: f.SoftID, BaseURL: f.BaseURL, TaskURL: f.TaskURL}
+	for _, d := range []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{"http_timeout", f.HTTPTimeout, &cfg.HTTPTimeout},
+		{"max_wait", f.MaxWait, &cfg.MaxWait},
+		{"poll_interval", f.PollInterval, &cfg.PollInterval},
+	} {
+		if err := parseDuration(d.name, d.value, d.dst); err != nil {
+			return Config{}, err
+		}
+	}
+	return cfg, nil
+}
+
+// NewFromEnv creates a client configured by the environment:
+// TWOCAPTCHA_API_KEY, TWOCAPTCHA_SOFT_ID, TWOCAPTCHA_BASE_URL,
+// TWOCAPTCHA_TASK_URL, TWOCAPTCHA_HTTP_TIMEOUT, TWOCAPTCHA_MAX_WAIT and
+// TWOCAPTCHA_POLL_INTERVAL. The durations are strings like "30s".
+// If TWOCAPTCHA_CONFIG is set, the JSON file it points to is loaded with
+// LoadConfig first and the environment overrides its values.
+// opts are applied after the configuration.
+// ErrAPIKeyRequired is returned if no API key was configured.
+func NewFromEnv(opts ...Option) (*TwoCaptchaClient, error) {
+	var cfg Config
+	if path := os.Getenv("TWOCAPTCHA_CONFIG"); path != "" {
+		var err error
+		if cfg, err = LoadConfig(path); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range []struct {
+		env string
+		dst *string
+	}{
+		{"TWOCAPTCHA_API_KEY", &cfg.APIKey},
+		{"TWOCAPTCHA_SOFT_ID", &cfg.SoftID},
+		{"TWOCAPTCHA_BASE_URL", &cfg.BaseURL},
+		{"TWOCAPTCHA_TASK_URL", &cfg.TaskURL},
+	} {
+		if v := os.Getenv(s.env); v != "" {
+			*s.dst = v
+		}
+	}
+	for _, d := range []struct {
+		env string
+		dst *time.Duration
+	}{
+		{"TWOCAPTCHA_HTTP_TIMEOUT", &cfg.HTTPTimeout},
+		{"TWOCAPTCHA_MAX_WAIT", &cfg.MaxWait},
+		{"TWOCAPTCHA_POLL_INTERVAL", &cfg.PollInterval},
+	} {
+		if err := parseDuration(d.env, os.Getenv(d.env), d.dst); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.APIKey == "" {
+		return nil, ErrAPIKeyRequired
+	}
+	return cfg.New(opts...), nil
+}
+
+// New creates a client with the settings of cfg, opts are applied after them
+func (cfg Config) New(opts ...Option) *TwoCaptchaClient {
+	var cfgOpts []Option
+	if cfg.BaseURL != "" {
+		cfgOpts = append(cfgOpts, WithBaseURL(cfg.BaseURL))
+	}
+	if cfg.TaskURL != "" {
+		cfgOpts = append(cfgOpts, WithTaskURL(cfg.TaskURL))
+	}
+	if cfg.HTTPTimeout > 0 {
+		cfgOpts = append(cfgOpts, WithHTTPTimeout(cfg.HTTPTimeout))
+	}
+	if cfg.MaxWait > 0 {
+		cfgOpts = append(cfgOpts, WithMaxWait(cfg.MaxWait))
+	}
+	if cfg.PollInterval > 0 {
+		cfgOpts = append(cfgOpts, WithPolling(cfg.PollInterval))
+	}
+	c := New(cfg.APIKey, append(cfgOpts, opts...)...)
+	if cfg.SoftID != "" && c.SoftID == "" {
+		c.SoftID = cfg.SoftID
+	}
+	return c
+}
+
+// parseDuration parses the duration setting name into dst if it is set
+func parseDuration(name, value string, dst *time.Duration) error {
+	if value == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return errors.New("Invalid duration of " + name + ": " + value)
+	}
+	*dst = d
+	return nil
+}
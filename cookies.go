@@ -0,0 +1,41 @@
+package twocaptcha
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// HTTPCookies converts the cookies of the result to http.Cookie values
+// scoped to the host of pageURL
+func (r CaptchaResult) HTTPCookies(pageURL string) ([]*http.Cookie, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Hostname() == "" {
+		return nil, errors.New("Invalid page URL: " + pageURL)
+	}
+	cookies := make([]*http.Cookie, 0, len(r.Cookies))
+	for name, value := range r.Cookies {
+		cookies = append(cookies, &http.Cookie{
+			Name:   name,
+			Value:  value,
+			Domain: u.Hostname(),
+			Path:   "/",
+		})
+	}
+	return cookies, nil
+}
+
+// ApplyCookies stores the cookies of the result in jar for pageURL, so the
+// follow-up requests of the session use the cookies of the solving worker
+func ApplyCookies(jar http.CookieJar, pageURL string, r CaptchaResult) error {
+	cookies, err := r.HTTPCookies(pageURL)
+	if err != nil {
+		return err
+	}
+	u, _ := url.Parse(pageURL)
+	jar.SetCookies(u, cookies)
+	return nil
+}
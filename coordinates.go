@@ -0,0 +1,41 @@
+package twocaptcha
+
+import (
+	"context"
+	"encoding/base64"
+	"time"
+)
+
+// SolveCoordinates performs a coordinates captcha solving request to 2captcha.com
+// and returns with the points clicked by the worker and captcha ID if the
+// request was successful. instructions tells the worker where to click,
+// e.g. "click all traffic lights". An example of the objects to click can
+// be attached with WithInstructionImage.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#coordinates
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type CoordinatesTask.
+func (c *TwoCaptchaClient) SolveCoordinates(image []byte, instructions string, delay time.Duration, retries int, opts ...SolveOption) (Polygon, string, error) {
+	return c.SolveCoordinatesWithContext(context.Background(), image, instructions, delay, retries, opts...)
+}
+
+// SolveCoordinatesWithContext is SolveCoordinates with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type CoordinatesTask.
+func (c *TwoCaptchaClient) SolveCoordinatesWithContext(ctx context.Context, image []byte, instructions string, delay time.Duration, retries int, opts ...SolveOption) (Polygon, string, error) {
+	params := map[string]string{
+		"method":             "base64",
+		"body":               base64.StdEncoding.EncodeToString(image),
+		"coordinatescaptcha": "1",
+	}
+	if instructions != "" {
+		params["textinstructions"] = instructions
+	}
+	res, err := c.solveProxyless(ctx, params, delay, retries, opts)
+	if err != nil {
+		return nil, res.ID, err
+	}
+	points, err := ParsePolygon(res.Answer)
+	return points, res.ID, err
+}
@@ -0,0 +1,27 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+)
+
+// SolveCustom solves a captcha of any in.php method, e.g. a method added to
+// 2captcha after this client. params are sent next to the method as they
+// are, the solve options, the Proxy of the client and the polling are
+// applied as for the built-in captcha types. Answer of the result is the raw
+// answer of the API, see CustomTask for the JSON API v2.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api
+func (c *TwoCaptchaClient) SolveCustom(ctx context.Context, method string, params map[string]string, opts ...SolveOption) (CaptchaResult, error) {
+	if method == "" {
+		return CaptchaResult{}, errors.New("Method is required")
+	}
+	p := make(map[string]string, len(params)+1)
+	for k, v := range params {
+		p[k] = v
+	}
+	p["method"] = method
+
+	delay, retries := c.polling()
+	return c.solve(ctx, p, delay, retries, opts)
+}
@@ -0,0 +1,41 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// SolveCutcaptcha performs a Cutcaptcha solving request to 2captcha.com
+// and returns with the solved captcha and captcha ID if the request was successful.
+// captchaAPIKey is the API key of the captcha on the site, not the 2captcha ApiKey.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#cutcaptcha
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type CutCaptchaTaskProxyless.
+func (c *TwoCaptchaClient) SolveCutcaptcha(siteURL, miseryKey, captchaAPIKey string, delay time.Duration, retries int, opts ...SolveOption) (string, string, error) {
+	return c.SolveCutcaptchaWithContext(context.Background(), siteURL, miseryKey, captchaAPIKey, delay, retries, opts...)
+}
+
+// SolveCutcaptchaWithContext is SolveCutcaptcha with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type CutCaptchaTaskProxyless.
+func (c *TwoCaptchaClient) SolveCutcaptchaWithContext(ctx context.Context, siteURL, miseryKey, captchaAPIKey string, delay time.Duration, retries int, opts ...SolveOption) (string, string, error) {
+	if siteURL == "" || miseryKey == "" || captchaAPIKey == "" {
+		return "", "", errors.New("siteURL, miseryKey and captchaAPIKey are required")
+	}
+	res, err := c.solve(
+		ctx,
+		map[string]string{
+			"misery_key": miseryKey,
+			"api_key":    captchaAPIKey,
+			"pageurl":    siteURL,
+			"method":     "cutcaptcha",
+		},
+		delay,
+		retries,
+		opts,
+	)
+	return res.Answer, res.ID, err
+}
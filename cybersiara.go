@@ -0,0 +1,34 @@
+package twocaptcha
+
+import (
+	"context"
+	"time"
+)
+
+// SolveCyberSiARA performs a CyberSiARA captcha solving request to 2captcha.com
+// and returns with the solved captcha and captcha ID if the request was successful.
+// masterURLID is the SlideMasterUrlId value of the captcha on the page.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#cybersiara
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type AntiCyberSiAraTaskProxyless.
+func (c *TwoCaptchaClient) SolveCyberSiARA(masterURLID, siteURL, userAgent string, delay time.Duration, retries int, opts ...SolveOption) (string, string, error) {
+	return c.SolveCyberSiARAWithContext(context.Background(), masterURLID, siteURL, userAgent, delay, retries, opts...)
+}
+
+// SolveCyberSiARAWithContext is SolveCyberSiARA with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type AntiCyberSiAraTaskProxyless.
+func (c *TwoCaptchaClient) SolveCyberSiARAWithContext(ctx context.Context, masterURLID, siteURL, userAgent string, delay time.Duration, retries int, opts ...SolveOption) (string, string, error) {
+	params := map[string]string{
+		"master_url_id": masterURLID,
+		"pageurl":       siteURL,
+		"method":        "cybersiara",
+	}
+	if userAgent != "" {
+		params["userAgent"] = userAgent
+	}
+	res, err := c.solve(ctx, params, delay, retries, opts)
+	return res.Answer, res.ID, err
+}
@@ -0,0 +1,47 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// SolveDataDome performs a DataDome captcha solving request to 2captcha.com
+// and returns with the datadome cookie and captcha ID if the request was successful.
+// captchaURL is the URL of the captcha iframe. DataDome binds the cookie to
+// the IP address and the user agent, so the worker solves the captcha through
+// proxy with userAgent, use the same proxy and user agent for the page.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#datadome
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type DataDomeSliderTask.
+func (c *TwoCaptchaClient) SolveDataDome(captchaURL, siteURL, userAgent string, proxy *Proxy, delay time.Duration, retries int, opts ...SolveOption) (string, string, error) {
+	return c.SolveDataDomeWithContext(context.Background(), captchaURL, siteURL, userAgent, proxy, delay, retries, opts...)
+}
+
+// SolveDataDomeWithContext is SolveDataDome with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type DataDomeSliderTask.
+func (c *TwoCaptchaClient) SolveDataDomeWithContext(ctx context.Context, captchaURL, siteURL, userAgent string, proxy *Proxy, delay time.Duration, retries int, opts ...SolveOption) (string, string, error) {
+	if proxy == nil {
+		return "", "", errors.New("Proxy is required for DataDome")
+	}
+	if userAgent == "" {
+		return "", "", errors.New("UserAgent is required for DataDome")
+	}
+	res, err := c.solve(
+		ctx,
+		map[string]string{
+			"captcha_url": captchaURL,
+			"pageurl":     siteURL,
+			"userAgent":   userAgent,
+			"method":      "datadome",
+		},
+		delay,
+		retries,
+		// the proxy of the call overrides the proxies set by opts
+		append(opts[:len(opts):len(opts)], WithProxy(proxy)),
+	)
+	return res.Answer, res.ID, err
+}
@@ -0,0 +1,101 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+// ErrCaptchaNotFound is returned by DetectCaptcha if the page has no
+// captcha of a supported type
+var ErrCaptchaNotFound = errors.New("No supported captcha found on the page")
+
+var (
+	turnstilePattern        = regexp.MustCompile(`challenges\.cloudflare\.com/turnstile|class\s*=\s*["'][^"']*\bcf-turnstile\b`)
+	turnstileSiteKeyPattern = regexp.MustCompile(`["'](0x[\w-]{10,})["']`)
+	hcaptchaPattern         = regexp.MustCompile(`hcaptcha\.com/1/api\.js|class\s*=\s*["'][^"']*\bh-captcha\b`)
+	funcaptchaPattern       = regexp.MustCompile(`(?:https?:)?//([\w.-]*(?:arkoselabs|funcaptcha)\.com)/(?:v2|fc)/([0-9A-Fa-f-]{36})/`)
+	funcaptchaPKeyPattern   = regexp.MustCompile(`data-pkey\s*=\s*["']([0-9A-Fa-f-]{36})["']`)
+	geetestV4Pattern        = regexp.MustCompile(`["']?captcha_?[iI]d["']?\s*[:=]\s*["']([0-9a-f]{32})["']`)
+	geetestGTPattern        = regexp.MustCompile(`["']?gt["']?\s*[:=]\s*["']([0-9a-f]{32})["']`)
+	geetestChallengePattern = regexp.MustCompile(`["']?challenge["']?\s*[:=]\s*["']([0-9a-z]{32,})["']`)
+)
+
+// DetectCaptcha finds the captcha of a page and returns the SiteProfile
+// solving it. reCAPTCHA v2 and v3, hCaptcha, Turnstile, FunCaptcha and
+// GeeTest v3 and v4 are recognized. Like ExtractRecaptchaParams, the page is
+// matched with patterns of the usual widget and script markup, not parsed.
+// ErrCaptchaNotFound is returned if the page has no captcha of these types.
+func DetectCaptcha(html string) (SiteProfile, error) {
+	siteKey := func(patterns ...*regexp.Regexp) string {
+		for _, p := range patterns {
+			if m := p.FindStringSubmatch(html); m != nil {
+				return m[1]
+			}
+		}
+		return ""
+	}
+
+	switch {
+	case turnstilePattern.MatchString(html):
+		if key := siteKey(turnstileSiteKeyPattern); key != "" {
+			p := SiteProfile{Type: TurnstileType, SiteKey: key}
+			if m := recaptchaActionPattern.FindStringSubmatch(html); m != nil {
+				p.Action = m[1] + m[2]
+			}
+			return p, nil
+		}
+	case hcaptchaPattern.MatchString(html):
+		if key := siteKey(recaptchaSiteKeyPatterns...); key != "" {
+			return SiteProfile{Type: HCaptchaType, SiteKey: key, Invisible: recaptchaInvisiblePattern.MatchString(html)}, nil
+		}
+	}
+	if m := funcaptchaPattern.FindStringSubmatch(html); m != nil {
+		return SiteProfile{Type: FunCaptchaType, SiteKey: m[2], Params: map[string]string{"surl": "https://" + m[1]}}, nil
+	}
+	if key := siteKey(funcaptchaPKeyPattern); key != "" {
+		return SiteProfile{Type: FunCaptchaType, SiteKey: key}, nil
+	}
+	if key := siteKey(geetestV4Pattern); key != "" {
+		return SiteProfile{Type: GeeTestV4Type, SiteKey: key}, nil
+	}
+	if gt, challenge := siteKey(geetestGTPattern), siteKey(geetestChallengePattern); gt != "" && challenge != "" {
+		return SiteProfile{Type: GeeTestType, SiteKey: gt, Params: map[string]string{"challenge": challenge}}, nil
+	}
+
+	key, opts, err := ExtractRecaptchaParams(html)
+	if err != nil {
+		return SiteProfile{}, ErrCaptchaNotFound
+	}
+	p := SiteProfile{
+		Type:       RecaptchaV2Type,
+		SiteKey:    key,
+		Invisible:  opts.Invisible,
+		Enterprise: opts.Enterprise,
+		Action:     opts.Action,
+	}
+	if opts.Version == "v3" {
+		p.Type = RecaptchaV3Type
+	}
+	if opts.DataS != "" || opts.Domain != "" {
+		p.Params = make(map[string]string)
+		if opts.DataS != "" {
+			p.Params["data-s"] = opts.DataS
+		}
+		if opts.Domain != "" {
+			p.Params["domain"] = opts.Domain
+		}
+	}
+	return p, nil
+}
+
+// SolveDetected detects the captcha of the html of pageURL with DetectCaptcha
+// and solves it, e.g. for crawlers visiting sites with different captchas.
+// The site profiles registered with RegisterSite are not used.
+func (c *TwoCaptchaClient) SolveDetected(ctx context.Context, pageURL, html string, opts ...SolveOption) (CaptchaResult, error) {
+	p, err := DetectCaptcha(html)
+	if err != nil {
+		return CaptchaResult{}, err
+	}
+	return c.solveSite(ctx, pageURL, p, opts)
+}
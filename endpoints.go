@@ -0,0 +1,136 @@
+package twocaptcha
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// endpointRetryAfter is the time an unreachable endpoint is not used for
+const endpointRetryAfter = time.Minute
+
+// endpoints are the alternative base URLs of the in.php and res.php of a client
+type endpoints struct {
+	mu   sync.Mutex
+	urls []string
+	// latency is the moving average of the response times, zero until the
+	// first response so that every endpoint is measured
+	latency []time.Duration
+	down    []time.Time
+}
+
+// WithBaseURLs sends the in.php and res.php requests of the client to the
+// fastest of several base URLs, e.g. the alternative domains of 2captcha or
+// reverse proxies in different regions. The endpoint with the lowest
+// average response time is used. An endpoint which can not be reached is
+// skipped for a minute, the request is sent to the next endpoint: always for
+// the polls and only if the connection failed for the submissions, to never
+// pay for a captcha twice. The first URL is the base URL of the client as
+// set by WithBaseURL. The JSON API v2 is not affected.
+func WithBaseURLs(baseURLs ...string) Option {
+	return func(c *TwoCaptchaClient) {
+		if len(baseURLs) == 0 {
+			return
+		}
+		e := &endpoints{latency: make([]time.Duration, len(baseURLs)), down: make([]time.Time, len(baseURLs))}
+		for _, u := range baseURLs {
+			e.urls = append(e.urls, strings.TrimSuffix(u, "/"))
+		}
+		c.baseURL = e.urls[0]
+		c.endpoints = e
+	}
+}
+
+// pick returns the index of the fastest endpoint which is up and was not
+// tried yet, the index of the first untried endpoint if all of them are down
+// and -1 if all of them were tried
+func (e *endpoints) pick(tried map[int]bool) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	best, fallback := -1, -1
+	for i := range e.urls {
+		if tried[i] {
+			continue
+		}
+		if fallback < 0 {
+			fallback = i
+		}
+		if time.Now().Before(e.down[i]) {
+			continue
+		}
+		if best < 0 || e.latency[i] < e.latency[best] {
+			best = i
+		}
+	}
+	if best < 0 {
+		return fallback
+	}
+	return best
+}
+
+// observe records the response time of an endpoint or marks it down
+func (e *endpoints) observe(i int, d time.Duration, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err != nil {
+		e.down[i] = time.Now().Add(endpointRetryAfter)
+		return
+	}
+	e.down[i] = time.Time{}
+	if e.latency[i] == 0 {
+		e.latency[i] = d
+	} else {
+		e.latency[i] = (3*e.latency[i] + d) / 4
+	}
+}
+
+// httpDoEndpoint performs a request of the base URL of the client with the
+// endpoints of WithBaseURLs
+func (c *TwoCaptchaClient) httpDoEndpoint(req *http.Request, idempotent bool) (*http.Response, error) {
+	if c.endpoints == nil || !strings.HasPrefix(req.URL.String(), c.baseURL) {
+		return c.httpDo(req)
+	}
+	path := strings.TrimPrefix(req.URL.String(), c.baseURL)
+	tried := make(map[int]bool)
+	var err error
+	for i := c.endpoints.pick(tried); i >= 0; i = c.endpoints.pick(tried) {
+		tried[i] = true
+		if len(tried) > 1 && req.GetBody == nil {
+			// the body was consumed by the failed attempt
+			return nil, err
+		}
+		r := req.WithContext(req.Context())
+		if r.URL, err = url.Parse(c.endpoints.urls[i] + path); err != nil {
+			return nil, err
+		}
+		r.Host = ""
+		if len(tried) > 1 {
+			if r.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+		start := time.Now()
+		var resp *http.Response
+		resp, err = c.httpDo(r)
+		if req.Context().Err() == nil {
+			// a request aborted by its context says nothing about the endpoint
+			c.endpoints.observe(i, time.Since(start), err)
+		}
+		if err == nil || req.Context().Err() != nil || !(idempotent || isDialError(err)) {
+			return resp, err
+		}
+		c.logf("2captcha: endpoint %s failed: %v", c.endpoints.urls[i], err)
+	}
+	return nil, err
+}
+
+// isDialError reports whether err is a failure to connect, the request was
+// not sent
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
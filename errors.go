@@ -0,0 +1,167 @@
+package twocaptcha
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrAPIKeyRequired is returned by the API calls of a client without ApiKey
+var ErrAPIKeyRequired = errors.New("API key required")
+
+// Errors returned by the API. The returned errors are *APIError values carrying
+// the raw error code, use errors.Is to check them, e.g.
+// errors.Is(err, twocaptcha.ErrZeroBalance)
+var (
+	// ErrWrongUserKey is returned if the API key is invalid or does not exist
+	ErrWrongUserKey = errors.New("API key is invalid")
+	// ErrZeroBalance is returned if the balance of the account is empty
+	ErrZeroBalance = errors.New("Account balance is zero")
+	// ErrNoSlotAvailable is returned if the queue of the account is full or
+	// the maximum rate of the account is exceeded, retry later
+	ErrNoSlotAvailable = errors.New("No slot available")
+)
+
+// ErrTimeout is returned if the captcha was not solved within the polling
+// attempts or the deadline of the solve. The captcha ID is still returned,
+// the answer can be fetched later. The errors of the submitted captchas are
+// *TimeoutError values.
+var ErrTimeout = errors.New("Timeout waiting for the captcha")
+
+// TimeoutError is returned if a submitted captcha was not solved within the
+// polling attempts or the deadline of the solve. It matches both ErrTimeout
+// and ErrNotReady, the captcha is still being solved and can be polled
+// further with its ID, e.g. with Await.
+type TimeoutError struct {
+	// CaptchaID is the ID of the captcha
+	CaptchaID string
+}
+
+func (e *TimeoutError) Error() string {
+	return "Timeout waiting for the captcha " + e.CaptchaID
+}
+
+// Is reports whether target is ErrTimeout or ErrNotReady
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout || target == ErrNotReady
+}
+
+// ErrCaptchaUnsolvable is returned if the workers could not solve the captcha.
+// The captcha ID can not be polled anymore, the captcha must be resubmitted.
+var ErrCaptchaUnsolvable = errors.New("Captcha is unsolvable")
+
+// ErrIPNotAllowed is returned if the IP address of the client is not allowed
+// to use the API key because of the IP restriction of the account
+var ErrIPNotAllowed = errors.New("IP address is not allowed, add it to the allowed IPs in the account settings on 2captcha.com")
+
+// ErrTokenExpired is returned if the data passed with the captcha, e.g. the
+// data-s or blob value, is stale. Fetch fresh data from the page and
+// resubmit the captcha instead of retrying with the same data.
+var ErrTokenExpired = errors.New("Captcha data is expired, refresh it and resubmit the captcha")
+
+// ErrScoreOutOfRange is returned if no reCAPTCHA v3 token was found with
+// a score within the requested range
+var ErrScoreOutOfRange = errors.New("Captcha score is out of range")
+
+// APIError is an error code returned by the API, e.g. ERROR_ZERO_BALANCE
+type APIError struct {
+	Code string
+	// Description is the description of the error, JSONFormat and JSON API v2 only
+	Description string
+}
+
+func (e *APIError) Error() string {
+	if err, ok := apiErrors[e.Code]; ok {
+		return "2captcha error " + e.Code + ": " + err.Error()
+	}
+	if e.Description != "" {
+		return "2captcha error " + e.Code + ": " + e.Description
+	}
+	return "2captcha error: " + e.Code
+}
+
+// Is reports whether the error code of e matches target
+func (e *APIError) Is(target error) bool {
+	return apiErrors[e.Code] == target
+}
+
+// apiErrors maps the API error codes to the error values of the package
+var apiErrors = map[string]error{
+	CodeWrongUserKey:      ErrWrongUserKey,
+	CodeKeyDoesNotExist:   ErrWrongUserKey,
+	CodeZeroBalance:       ErrZeroBalance,
+	CodeNoSlotAvailable:   ErrNoSlotAvailable,
+	CodeCaptchaUnsolvable: ErrCaptchaUnsolvable,
+	CodeIPNotAllowed:      ErrIPNotAllowed,
+	CodeTokenExpired:      ErrTokenExpired,
+	CodeReportNotRecorded: ErrReportNotRecorded,
+	CodeDuplicateReport:   ErrDuplicateReport,
+}
+
+// DefaultResultErrorClassifier is the built-in classification of the errors
+// returned while polling res.php. Only ERROR_NO_SLOT_AVAILABLE is retried,
+// the other errors are permanent for the captcha ID.
+func DefaultResultErrorClassifier(code string) bool {
+	return code == CodeNoSlotAvailable
+}
+
+// retryResultError reports whether a res.php error code is retried
+func (c *TwoCaptchaClient) retryResultError(code string) bool {
+	if c.ResultErrorClassifier != nil {
+		return c.ResultErrorClassifier(code)
+	}
+	return DefaultResultErrorClassifier(code)
+}
+
+// transientError is a network error or a server error of an API request,
+// the request can be retried
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string {
+	return e.err.Error()
+}
+
+func (e *transientError) Unwrap() error {
+	return e.err
+}
+
+// isTransient reports whether err is a transientError
+func isTransient(err error) bool {
+	_, ok := err.(*transientError)
+	return ok
+}
+
+// ErrMalformedResponse is matched by the errors returned for the API
+// responses which could not be parsed, e.g. an empty body returned by a proxy
+// or an OK without the answer. The errors are *MalformedResponseError values
+// holding the raw body.
+var ErrMalformedResponse = errors.New("Malformed API response")
+
+// MalformedResponseError is an API response which could not be parsed
+type MalformedResponseError struct {
+	// Body is the raw body of the response
+	Body []byte
+	// Err is the error of the parsing if any
+	Err error
+}
+
+func (e *MalformedResponseError) Error() string {
+	msg := "Malformed API response " + strconv.Quote(truncate(string(e.Body)))
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Is reports whether target is ErrMalformedResponse
+func (e *MalformedResponseError) Is(target error) bool {
+	return target == ErrMalformedResponse
+}
+
+func (e *MalformedResponseError) Unwrap() error {
+	return e.Err
+}
+
+// ErrNotReady is returned by Result if the captcha is not solved yet
+var ErrNotReady = errors.New("Captcha is not ready")
@@ -0,0 +1,387 @@
+package twocaptcha_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"log"
+
+	"github.com/gocolly/twocaptcha"
+)
+
+// The examples solve the captchas in the sandbox of the client, replace
+// WithSandbox with a real API key to solve them on 2captcha.
+
+// captchaImage returns a PNG image standing in for a captcha screenshot
+func captchaImage() []byte {
+	img := image.NewGray(image.Rect(0, 0, 120, 40))
+	for x := 0; x < 120; x++ {
+		for y := 0; y < 40; y++ {
+			img.SetGray(x, y, color.Gray{Y: uint8(x * y * 7)})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		log.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func ExampleTwoCaptchaClient_SolveRecaptcha() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox(""))
+
+	token, captchaId, err := client.SolveRecaptcha("https://www.google.com/recaptcha/api2/demo", "6Le-wvkSAAAAAPBMRTvw0Q4Muexq9bi0DJwx_mJ-", twocaptcha.RecaptchaOptions{}, 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(token, captchaId)
+	// Output: SANDBOX_TOKEN 1
+}
+
+func ExampleTwoCaptchaClient_SolveRecaptchaV3Result() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox(""))
+	ctx := context.Background()
+
+	res, err := client.SolveRecaptchaV3Result(ctx, "https://example.com/login", "SITE_KEY", "login", 0.7)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(res.Answer)
+	// report the token once the site accepted or rejected it
+	if err := client.ReportResult(ctx, res, true); err != nil {
+		log.Fatal(err)
+	}
+	// Output: SANDBOX_TOKEN
+}
+
+func ExampleTwoCaptchaClient_SolveHCaptcha() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("P1_eyJ0eXAiOiJKV1Qi"))
+
+	token, _, err := client.SolveHCaptcha("https://example.com", "SITE_KEY", twocaptcha.HCaptchaOptions{}, 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(token)
+	// Output: P1_eyJ0eXAiOiJKV1Qi
+}
+
+func ExampleTwoCaptchaClient_SolveTurnstile() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("0.turnstile_token"))
+
+	token, _, err := client.SolveTurnstile("https://example.com", "0x4AAAAAAAB", twocaptcha.TurnstileOptions{Action: "login"}, 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(token)
+	// Output: 0.turnstile_token
+}
+
+func ExampleTwoCaptchaClient_SolveCloudflareChallenge() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("0.cf_token"))
+
+	ch, err := twocaptcha.ParseCloudflareChallenge([]byte(`{"sitekey":"0x4AAAAAAAB","action":"managed","cData":"c","chlPageData":"p","userAgent":"Mozilla/5.0"}`))
+	if err != nil {
+		log.Fatal(err)
+	}
+	res, err := client.SolveCloudflareChallenge(context.Background(), "https://example.com", ch)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(res.Answer)
+	// Output: 0.cf_token
+}
+
+func ExampleTwoCaptchaClient_SolveFunCaptcha() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("3084f4a302b176cd7.96368058|r=ap-southeast-1"))
+
+	token, _, err := client.SolveFunCaptcha("https://example.com", "PUBLIC_KEY", "", nil, 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(token)
+	// Output: 3084f4a302b176cd7.96368058|r=ap-southeast-1
+}
+
+func ExampleTwoCaptchaClient_SolveGeeTest() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox(`{"geetest_challenge":"c1","geetest_validate":"v1","geetest_seccode":"v1|jordan"}`))
+
+	res, _, err := client.SolveGeeTest("GT", "CHALLENGE", "https://example.com", 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(res.Challenge, res.Validate, res.Seccode)
+	// Output: c1 v1 v1|jordan
+}
+
+func ExampleTwoCaptchaClient_SolveGeeTestV4() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox(`{"captcha_id":"id","lot_number":"lot","pass_token":"pass","gen_time":"1700000000","captcha_output":"out"}`))
+
+	res, _, err := client.SolveGeeTestV4("CAPTCHA_ID", "https://example.com", nil, 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(res.LotNumber, res.PassToken)
+	// Output: lot pass
+}
+
+func ExampleTwoCaptchaClient_SolveKeyCaptcha() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("d58a7d6e9b5b2c9d|0"))
+
+	answer, _, err := client.SolveKeyCaptcha("USER_ID", "SESSION_ID", "SIGN", "SIGN2", "https://example.com", 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(answer)
+	// Output: d58a7d6e9b5b2c9d|0
+}
+
+func ExampleTwoCaptchaClient_SolveCapy() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("captchakey:PUZZLE_key;challengekey:ch;answer:0xax8h"))
+
+	res, _, err := client.SolveCapy("PUZZLE_key", "https://example.com", "", 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(res.CaptchaKey, res.ChallengeKey, res.Answer)
+	// Output: PUZZLE_key ch 0xax8h
+}
+
+func ExampleTwoCaptchaClient_SolveLemin() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox(`{"answer":"0xaxa","challenge_id":"e0348984"}`))
+
+	res, _, err := client.SolveLemin("CROPPED_ID", "lemin-cropped-captcha", "https://example.com", 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(res.Answer, res.ChallengeID)
+	// Output: 0xaxa e0348984
+}
+
+func ExampleTwoCaptchaClient_SolveAmazonWAF() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox(`{"captcha_voucher":"voucher","existing_token":"token"}`))
+
+	res, _, err := client.SolveAmazonWAF("SITE_KEY", "IV", "CONTEXT", "https://example.com", 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(res.CaptchaVoucher, res.ExistingToken)
+	// Output: voucher token
+}
+
+func ExampleTwoCaptchaClient_SolveTencent() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox(`{"appid":"190014885","ticket":"tr034","randstr":"@KVN"}`))
+
+	res, _, err := client.SolveTencent("190014885", "https://example.com", 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(res.Ticket, res.RandStr)
+	// Output: tr034 @KVN
+}
+
+func ExampleTwoCaptchaClient_SolveMTCaptcha() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("v1(03,79a,MTPublic-abc)"))
+
+	token, _, err := client.SolveMTCaptcha("MTPublic-abc", "https://example.com", 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(token)
+	// Output: v1(03,79a,MTPublic-abc)
+}
+
+func ExampleTwoCaptchaClient_SolveFriendlyCaptcha() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("friendly_token"))
+
+	token, _, err := client.SolveFriendlyCaptcha("SITE_KEY", "https://example.com", 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(token)
+	// Output: friendly_token
+}
+
+func ExampleTwoCaptchaClient_SolveCutcaptcha() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("cut_token"))
+
+	token, _, err := client.SolveCutcaptcha("https://example.com", "MISERY_KEY", "API_KEY", 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(token)
+	// Output: cut_token
+}
+
+func ExampleTwoCaptchaClient_SolveCyberSiARA() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("siara_token"))
+
+	token, _, err := client.SolveCyberSiARA("MASTER_URL_ID", "https://example.com", "Mozilla/5.0", 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(token)
+	// Output: siara_token
+}
+
+func ExampleTwoCaptchaClient_SolveYandexSmartCaptcha() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("dD0xNzA4"))
+
+	res, err := client.SolveYandexSmartCaptcha(context.Background(), "SITE_KEY", "https://example.com")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(res.Answer)
+	// Output: dD0xNzA4
+}
+
+func ExampleTwoCaptchaClient_SolveDataDome() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("datadome=4ZXwCBlyHx9ktZhSnycMF"))
+
+	proxy := &twocaptcha.Proxy{Type: "HTTP", Address: "203.0.113.7:8080", Login: "user", Password: "secret"}
+	cookie, _, err := client.SolveDataDome("https://geo.captcha-delivery.com/captcha/?initialCid=abc", "https://example.com", "Mozilla/5.0", proxy, 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(cookie)
+	// Output: datadome=4ZXwCBlyHx9ktZhSnycMF
+}
+
+func ExampleTwoCaptchaClient_SolveImageCaptcha() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("W9H5K"))
+
+	res, err := client.SolveImageCaptcha(context.Background(), captchaImage(), twocaptcha.ImageOptions{CaseSensitive: true})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(res.Answer)
+	// Output: W9H5K
+}
+
+func ExampleTwoCaptchaClient_SolveTextCaptcha() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("4"))
+
+	res, err := client.SolveTextCaptcha(context.Background(), "How much is 2 + 2?", "en")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(res.Answer)
+	// Output: 4
+}
+
+func ExampleTwoCaptchaClient_SolveAudioCaptcha() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("hello world"))
+
+	audio := []byte("ID3 stand-in for the MP3 of the audio captcha")
+	res, err := client.SolveAudioCaptcha(context.Background(), audio, "en")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(res.Answer)
+	// Output: hello world
+}
+
+func ExampleTwoCaptchaClient_SolveGrid() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("click:1/4/7"))
+
+	cells, _, err := client.SolveGrid(captchaImage(), twocaptcha.GridOptions{Rows: 3, Cols: 3, TextInstructions: "select all cars"}, 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(cells)
+	// Output: [1 4 7]
+}
+
+func ExampleTwoCaptchaClient_SolveCoordinates() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("coordinates:x=39,y=59;x=252,y=72"))
+
+	points, _, err := client.SolveCoordinates(captchaImage(), "click the animals", 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(points)
+	// Output: x=39,y=59;x=252,y=72
+}
+
+func ExampleTwoCaptchaClient_SolveCanvas() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("canvas:x=10,y=10;x=50,y=10;x=30,y=40"))
+
+	outline, _, err := client.SolveCanvas(captchaImage(), "outline the triangle", 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(outline), "points")
+	// Output: 3 points
+}
+
+func ExampleTwoCaptchaClient_SolveBoundingBox() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("x=10,y=5;x=60,y=35"))
+
+	boxes, _, err := client.SolveBoundingBox(captchaImage(), "box the car", 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(boxes)
+	// Output: [(10,5)-(60,35)]
+}
+
+func ExampleTwoCaptchaClient_SolveRotateCaptcha() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("40"))
+
+	angles, _, err := client.SolveRotateCaptcha([][]byte{captchaImage()}, 40, 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(angles)
+	// Output: [40]
+}
+
+func ExampleTwoCaptchaClient_SolveSlider() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("coordinates:x=87,y=20"))
+
+	offset, _, err := client.SolveSlider(context.Background(), captchaImage(), nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(offset)
+	// Output: 87
+}
+
+func ExampleTwoCaptchaClient_SolveCustom() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox("custom_token"))
+
+	res, err := client.SolveCustom(context.Background(), "newcaptcha", map[string]string{"sitekey": "SITE_KEY", "pageurl": "https://example.com"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(res.Answer)
+	// Output: custom_token
+}
+
+func ExampleTwoCaptchaClient_Solve() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox(""))
+
+	res, err := client.Solve(context.Background(), twocaptcha.RecaptchaV2Task{
+		WebsiteURL: "https://www.google.com/recaptcha/api2/demo",
+		WebsiteKey: "6Le-wvkSAAAAAPBMRTvw0Q4Muexq9bi0DJwx_mJ-",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(res.Answer)
+	// Output: SANDBOX_TOKEN
+}
+
+func ExampleTwoCaptchaClient_SolveDetected() {
+	client := twocaptcha.New("YOUR_API_KEY", twocaptcha.WithSandbox(""))
+
+	html := `<div class="g-recaptcha" data-sitekey="6Le-wvkSAAAAAPBMRTvw0Q4Muexq9bi0DJwx_mJ-"></div>`
+	res, err := client.SolveDetected(context.Background(), "https://example.com", html)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(res.Answer)
+	// Output: SANDBOX_TOKEN
+}
@@ -0,0 +1,36 @@
+// Command grid solves the grid captcha of a file given as argument.
+//
+// The captcha is solved in the sandbox of the client unless the
+// TWOCAPTCHA_API_KEY environment variable is set, see NewFromEnv.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/gocolly/twocaptcha"
+)
+
+func main() {
+	client, err := twocaptcha.NewFromEnv()
+	if err == twocaptcha.ErrAPIKeyRequired {
+		client = twocaptcha.New("SANDBOX", twocaptcha.WithSandbox("click:1/5/9"))
+	} else if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(os.Args) != 2 {
+		log.Fatal("Usage: grid FILE")
+	}
+	img, err := ioutil.ReadFile(os.Args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+	cells, _, err := client.SolveGrid(img, twocaptcha.GridOptions{Rows: 3, Cols: 3, TextInstructions: "Select all images with cars"}, 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("cells:", cells)
+}
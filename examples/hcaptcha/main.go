@@ -0,0 +1,27 @@
+// Command hcaptcha solves an hCaptcha.
+//
+// The captcha is solved in the sandbox of the client unless the
+// TWOCAPTCHA_API_KEY environment variable is set, see NewFromEnv.
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/gocolly/twocaptcha"
+)
+
+func main() {
+	client, err := twocaptcha.NewFromEnv()
+	if err == twocaptcha.ErrAPIKeyRequired {
+		client = twocaptcha.New("SANDBOX", twocaptcha.WithSandbox(""))
+	} else if err != nil {
+		log.Fatal(err)
+	}
+
+	token, _, err := client.SolveHCaptcha("https://accounts.hcaptcha.com/demo", "a5f74b19-9e45-40e0-b45d-47ff91b7a6c2", twocaptcha.HCaptchaOptions{}, 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("token:", token)
+}
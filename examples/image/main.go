@@ -0,0 +1,37 @@
+// Command image solves the image captcha of a file given as argument.
+//
+// The captcha is solved in the sandbox of the client unless the
+// TWOCAPTCHA_API_KEY environment variable is set, see NewFromEnv.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/gocolly/twocaptcha"
+)
+
+func main() {
+	client, err := twocaptcha.NewFromEnv()
+	if err == twocaptcha.ErrAPIKeyRequired {
+		client = twocaptcha.New("SANDBOX", twocaptcha.WithSandbox("W68HP"))
+	} else if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(os.Args) != 2 {
+		log.Fatal("Usage: image FILE")
+	}
+	img, err := ioutil.ReadFile(os.Args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+	res, err := client.SolveImageCaptcha(context.Background(), img, twocaptcha.ImageOptions{CaseSensitive: true})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("answer:", res.Answer)
+}
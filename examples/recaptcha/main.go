@@ -0,0 +1,37 @@
+// Command recaptcha solves a reCAPTCHA v2 and reports its token.
+//
+// The captcha is solved in the sandbox of the client unless the
+// TWOCAPTCHA_API_KEY environment variable is set.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/gocolly/twocaptcha"
+	"github.com/gocolly/twocaptcha/solver"
+)
+
+func main() {
+	client := solver.New(os.Getenv("TWOCAPTCHA_API_KEY"))
+	if os.Getenv("TWOCAPTCHA_API_KEY") == "" {
+		client = solver.New("SANDBOX", twocaptcha.WithSandbox(""))
+	}
+	ctx := context.Background()
+
+	res, err := client.Solve(ctx, solver.RecaptchaV2Task{
+		WebsiteURL: "https://www.google.com/recaptcha/api2/demo",
+		WebsiteKey: "6Le-wvkSAAAAAPBMRTvw0Q4Muexq9bi0DJwx_mJ-",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("token:", res.Answer)
+
+	// report the token once the site accepted or rejected it
+	if err := client.Report(ctx, res, true); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,35 @@
+// Command task solves a reCAPTCHA v2 with the JSON API v2.
+//
+// The captcha is solved in the sandbox of the client unless the
+// TWOCAPTCHA_API_KEY environment variable is set, see NewFromEnv.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gocolly/twocaptcha"
+)
+
+func main() {
+	client, err := twocaptcha.NewFromEnv()
+	if err == twocaptcha.ErrAPIKeyRequired {
+		client = twocaptcha.New("SANDBOX", twocaptcha.WithSandbox(""))
+	} else if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+	res, err := client.SolveTask(ctx, twocaptcha.RecaptchaV2Task{
+		WebsiteURL: "https://www.google.com/recaptcha/api2/demo",
+		WebsiteKey: "6Le-wvkSAAAAAPBMRTvw0Q4Muexq9bi0DJwx_mJ-",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("token:", res.Answer)
+	fmt.Println("cost:", res.Cost)
+}
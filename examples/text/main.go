@@ -0,0 +1,28 @@
+// Command text solves a text captcha.
+//
+// The captcha is solved in the sandbox of the client unless the
+// TWOCAPTCHA_API_KEY environment variable is set, see NewFromEnv.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/gocolly/twocaptcha"
+)
+
+func main() {
+	client, err := twocaptcha.NewFromEnv()
+	if err == twocaptcha.ErrAPIKeyRequired {
+		client = twocaptcha.New("SANDBOX", twocaptcha.WithSandbox("4"))
+	} else if err != nil {
+		log.Fatal(err)
+	}
+
+	res, err := client.SolveTextCaptcha(context.Background(), "If tomorrow is Saturday, what day is today?", "en")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("answer:", res.Answer)
+}
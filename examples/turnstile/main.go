@@ -0,0 +1,27 @@
+// Command turnstile solves a Cloudflare Turnstile captcha.
+//
+// The captcha is solved in the sandbox of the client unless the
+// TWOCAPTCHA_API_KEY environment variable is set, see NewFromEnv.
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/gocolly/twocaptcha"
+)
+
+func main() {
+	client, err := twocaptcha.NewFromEnv()
+	if err == twocaptcha.ErrAPIKeyRequired {
+		client = twocaptcha.New("SANDBOX", twocaptcha.WithSandbox(""))
+	} else if err != nil {
+		log.Fatal(err)
+	}
+
+	token, _, err := client.SolveTurnstile("https://2captcha.com/demo/cloudflare-turnstile", "0x4AAAAAAAVrOwQWPlm3Bnr5", twocaptcha.TurnstileOptions{}, 5, 20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("token:", token)
+}
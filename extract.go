@@ -0,0 +1,65 @@
+package twocaptcha
+
+import (
+	"errors"
+	"regexp"
+)
+
+// ErrRecaptchaNotFound is returned by ExtractRecaptchaParams if the page has
+// no reCAPTCHA site key
+var ErrRecaptchaNotFound = errors.New("reCAPTCHA site key not found")
+
+var (
+	recaptchaSiteKeyPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`data-sitekey\s*=\s*["']([\w-]+)["']`),
+		regexp.MustCompile(`["']?sitekey["']?\s*:\s*["']([\w-]+)["']`),
+	}
+	// recaptchaRenderPattern matches the site key of a reCAPTCHA v3 script,
+	// render=explicit is the v2 explicit rendering
+	recaptchaRenderPattern     = regexp.MustCompile(`/recaptcha/(?:api|enterprise)\.js\?(?:[^"'\s]*&(?:amp;)?)?render=([\w-]+)`)
+	recaptchaExecutePattern    = regexp.MustCompile(`grecaptcha(?:\.enterprise)?\.execute\(\s*["']([\w-]+)["']`)
+	recaptchaEnterprisePattern = regexp.MustCompile(`/recaptcha/enterprise\.js|grecaptcha\.enterprise\.`)
+	recaptchaNetPattern        = regexp.MustCompile(`recaptcha\.net/recaptcha/`)
+	recaptchaInvisiblePattern  = regexp.MustCompile(`data-size\s*=\s*["']invisible["']|["']?size["']?\s*:\s*["']invisible["']`)
+	recaptchaActionPattern     = regexp.MustCompile(`data-action\s*=\s*["']([^"']+)["']|["']?action["']?\s*:\s*["']([^"']+)["']`)
+	recaptchaDataSPattern      = regexp.MustCompile(`data-s\s*=\s*["']([^"']+)["']`)
+)
+
+// ExtractRecaptchaParams finds the reCAPTCHA widget of a page and returns its
+// site key with the options to solve it with SolveRecaptcha: the version,
+// Enterprise, Invisible, Action, DataS and Domain. The page is matched with
+// patterns of the usual widget and script markup, not parsed, so widgets
+// configured by obfuscated scripts are not found.
+// ErrRecaptchaNotFound is returned if the page has no site key.
+func ExtractRecaptchaParams(html string) (string, RecaptchaOptions, error) {
+	var opts RecaptchaOptions
+	siteKey := ""
+	if m := recaptchaRenderPattern.FindStringSubmatch(html); m != nil && m[1] != "explicit" {
+		siteKey, opts.Version = m[1], "v3"
+	} else if m := recaptchaExecutePattern.FindStringSubmatch(html); m != nil {
+		siteKey, opts.Version = m[1], "v3"
+	} else {
+		for _, p := range recaptchaSiteKeyPatterns {
+			if m := p.FindStringSubmatch(html); m != nil {
+				siteKey, opts.Version = m[1], "v2"
+				break
+			}
+		}
+	}
+	if siteKey == "" {
+		return "", opts, ErrRecaptchaNotFound
+	}
+
+	opts.Enterprise = recaptchaEnterprisePattern.MatchString(html)
+	if recaptchaNetPattern.MatchString(html) {
+		opts.Domain = "recaptcha.net"
+	}
+	opts.Invisible = opts.Version == "v2" && recaptchaInvisiblePattern.MatchString(html)
+	if m := recaptchaActionPattern.FindStringSubmatch(html); m != nil {
+		opts.Action = m[1] + m[2]
+	}
+	if m := recaptchaDataSPattern.FindStringSubmatch(html); m != nil {
+		opts.DataS = m[1]
+	}
+	return siteKey, opts, nil
+}
@@ -0,0 +1,71 @@
+package twocaptcha
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultFreshMargin is the validity a token of FreshToken must have left,
+// the time needed to submit it to the site
+const DefaultFreshMargin = 10 * time.Second
+
+// ExpiresAt returns the time the token expires, the solve time plus its
+// validity. Validity defaults to DefaultTokenValidity if it is not set.
+func (r CaptchaResult) ExpiresAt() time.Time {
+	validity := r.Validity
+	if validity <= 0 {
+		validity = DefaultTokenValidity
+	}
+	return r.SolvedAt.Add(validity)
+}
+
+// FreshToken keeps a solved token and replaces it when it is about to expire,
+// e.g. for a form submitted some time after the captcha was solved.
+// It is safe for concurrent use, the token is shared by the callers.
+type FreshToken struct {
+	// Margin is the validity the token must have left when it is returned,
+	// DefaultFreshMargin if it is zero
+	Margin time.Duration
+
+	solve func(ctx context.Context) (CaptchaResult, error)
+	mu    sync.Mutex
+	res   CaptchaResult
+}
+
+// NewFreshToken creates a FreshToken solving its tokens with solve, e.g.
+//
+//	t := twocaptcha.NewFreshToken(func(ctx context.Context) (twocaptcha.CaptchaResult, error) {
+//		return client.SolveTask(ctx, task)
+//	})
+func NewFreshToken(solve func(ctx context.Context) (CaptchaResult, error)) *FreshToken {
+	return &FreshToken{solve: solve}
+}
+
+// Set replaces the token, e.g. with a result solved before the FreshToken
+// was created
+func (t *FreshToken) Set(res CaptchaResult) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.res = res
+}
+
+// EnsureFresh returns the token if it is valid for at least Margin more,
+// otherwise it solves a new token and returns it
+func (t *FreshToken) EnsureFresh(ctx context.Context) (CaptchaResult, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	margin := t.Margin
+	if margin <= 0 {
+		margin = DefaultFreshMargin
+	}
+	if t.res.Answer != "" && time.Until(t.res.ExpiresAt()) >= margin {
+		return t.res, nil
+	}
+	res, err := t.solve(ctx)
+	if err != nil {
+		return res, err
+	}
+	t.res = res
+	return res, nil
+}
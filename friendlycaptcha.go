@@ -0,0 +1,35 @@
+package twocaptcha
+
+import (
+	"context"
+	"time"
+)
+
+// SolveFriendlyCaptcha performs a Friendly Captcha solving request to 2captcha.com
+// and returns with the solved captcha and captcha ID if the request was successful.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#friendly-captcha
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type FriendlyCaptchaTaskProxyless.
+func (c *TwoCaptchaClient) SolveFriendlyCaptcha(siteKey, siteURL string, delay time.Duration, retries int, opts ...SolveOption) (string, string, error) {
+	return c.SolveFriendlyCaptchaWithContext(context.Background(), siteKey, siteURL, delay, retries, opts...)
+}
+
+// SolveFriendlyCaptchaWithContext is SolveFriendlyCaptcha with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type FriendlyCaptchaTaskProxyless.
+func (c *TwoCaptchaClient) SolveFriendlyCaptchaWithContext(ctx context.Context, siteKey, siteURL string, delay time.Duration, retries int, opts ...SolveOption) (string, string, error) {
+	res, err := c.solve(
+		ctx,
+		map[string]string{
+			"sitekey": siteKey,
+			"pageurl": siteURL,
+			"method":  "friendly_captcha",
+		},
+		delay,
+		retries,
+		opts,
+	)
+	return res.Answer, res.ID, err
+}
@@ -0,0 +1,43 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// SolveFunCaptcha performs a FunCaptcha (Arkose Labs) solving request to 2captcha.com
+// and returns with the solved captcha and captcha ID if the request was successful.
+// surl is the service URL of the captcha, it can be empty.
+// extraData is sent as data[key]=value fields, e.g. the blob value.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#solving_funcaptcha_new
+//
+// Deprecated: use the Solve of the solver package with a FunCaptchaTask.
+func (c *TwoCaptchaClient) SolveFunCaptcha(siteURL, publicKey, surl string, extraData map[string]string, delay time.Duration, retries int, opts ...SolveOption) (string, string, error) {
+	return c.SolveFunCaptchaWithContext(context.Background(), siteURL, publicKey, surl, extraData, delay, retries, opts...)
+}
+
+// SolveFunCaptchaWithContext is SolveFunCaptcha with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a FunCaptchaTask.
+func (c *TwoCaptchaClient) SolveFunCaptchaWithContext(ctx context.Context, siteURL, publicKey, surl string, extraData map[string]string, delay time.Duration, retries int, opts ...SolveOption) (string, string, error) {
+	params := map[string]string{
+		"publickey": publicKey,
+		"pageurl":   siteURL,
+		"method":    "funcaptcha",
+	}
+	if surl != "" {
+		params["surl"] = surl
+	}
+	for k, v := range extraData {
+		if k == "" || strings.ContainsAny(k, "[]") {
+			return "", "", errors.New("Invalid extra data key: " + k)
+		}
+		params["data["+k+"]"] = v
+	}
+	res, err := c.solve(ctx, params, delay, retries, opts)
+	return res.Answer, res.ID, err
+}
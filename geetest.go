@@ -0,0 +1,179 @@
+package twocaptcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// geeTestRefreshAttempts is the number of challenges solved by
+// SolveGeeTestFresh until one is not rejected as stale
+const geeTestRefreshAttempts = 3
+
+// GeeTestResult is a solved GeeTest v3 captcha
+type GeeTestResult struct {
+	Challenge string `json:"geetest_challenge"`
+	Validate  string `json:"geetest_validate"`
+	Seccode   string `json:"geetest_seccode"`
+}
+
+// GeeTestV4Result is a solved GeeTest v4 captcha
+type GeeTestV4Result struct {
+	CaptchaID     string `json:"captcha_id"`
+	LotNumber     string `json:"lot_number"`
+	PassToken     string `json:"pass_token"`
+	GenTime       string `json:"gen_time"`
+	CaptchaOutput string `json:"captcha_output"`
+}
+
+// SolveGeeTest performs a GeeTest v3 solving request to 2captcha.com
+// and returns with the solved captcha and captcha ID if the request was successful.
+// challenge must be fresh, it expires shortly after it was issued by the site.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#solving_geetest
+//
+// Deprecated: use the Solve of the solver package with a GeeTestTask.
+func (c *TwoCaptchaClient) SolveGeeTest(gt, challenge, siteURL string, delay time.Duration, retries int, opts ...SolveOption) (GeeTestResult, string, error) {
+	return c.SolveGeeTestWithContext(context.Background(), gt, challenge, siteURL, delay, retries, opts...)
+}
+
+// SolveGeeTestWithContext is SolveGeeTest with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a GeeTestTask.
+func (c *TwoCaptchaClient) SolveGeeTestWithContext(ctx context.Context, gt, challenge, siteURL string, delay time.Duration, retries int, opts ...SolveOption) (GeeTestResult, string, error) {
+	res, err := c.solve(
+		ctx,
+		map[string]string{
+			"gt":        gt,
+			"challenge": challenge,
+			"pageurl":   siteURL,
+			"method":    "geetest",
+		},
+		delay,
+		retries,
+		opts,
+	)
+	if err != nil {
+		return GeeTestResult{}, res.ID, err
+	}
+	var result GeeTestResult
+	if err := json.Unmarshal([]byte(res.Answer), &result); err != nil {
+		return result, res.ID, errors.New("Invalid GeeTest answer: " + res.Answer)
+	}
+	return result, res.ID, nil
+}
+
+// GeeTestChallengeFetcher fetches a fresh GeeTest v3 challenge from the site
+// and returns the gt and the challenge
+type GeeTestChallengeFetcher func(ctx context.Context) (gt, challenge string, err error)
+
+// GeeTestRegisterFetcher returns a GeeTestChallengeFetcher requesting
+// registerURL, the gettype or register endpoint of the site, with client.
+// The endpoint must return the usual {"success":1,"gt":"...","challenge":"..."}
+// response. A timestamp is added to the URL to bypass the caches.
+// http.DefaultClient is used if client is nil.
+func GeeTestRegisterFetcher(client *http.Client, registerURL string) GeeTestChallengeFetcher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context) (string, string, error) {
+		u, err := url.Parse(registerURL)
+		if err != nil {
+			return "", "", err
+		}
+		q := u.Query()
+		q.Set("t", strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10))
+		u.RawQuery = q.Encode()
+		req, err := http.NewRequest("GET", u.String(), nil)
+		if err != nil {
+			return "", "", err
+		}
+		resp, err := client.Do(req.WithContext(ctx))
+		if err != nil {
+			return "", "", err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", "", fmt.Errorf("Failed to fetch GeeTest challenge: %s", resp.Status)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return "", "", err
+		}
+		var r struct {
+			GT        string `json:"gt"`
+			Challenge string `json:"challenge"`
+		}
+		if err := json.Unmarshal(body, &r); err != nil || r.GT == "" || r.Challenge == "" {
+			return "", "", errors.New("Invalid GeeTest challenge: " + truncate(string(body)))
+		}
+		return r.GT, r.Challenge, nil
+	}
+}
+
+// SolveGeeTestFresh solves a GeeTest v3 captcha with SolveGeeTestWithContext
+// using a challenge fetched right before the submission. Stale challenges
+// are the most common cause of ErrCaptchaUnsolvable with GeeTest, so a new
+// challenge is fetched and solved if the captcha was unsolvable, up to 3 times.
+// The captchas are polled with the polling interval of the client.
+func (c *TwoCaptchaClient) SolveGeeTestFresh(ctx context.Context, fetch GeeTestChallengeFetcher, siteURL string, opts ...SolveOption) (GeeTestResult, string, error) {
+	var res GeeTestResult
+	var id string
+	var err error
+	delay, retries := c.polling()
+	for attempt := 1; attempt <= geeTestRefreshAttempts; attempt++ {
+		gt, challenge, fetchErr := fetch(ctx)
+		if fetchErr != nil {
+			return GeeTestResult{}, id, fetchErr
+		}
+		res, id, err = c.SolveGeeTestWithContext(ctx, gt, challenge, siteURL, delay, retries, opts...)
+		if !errors.Is(err, ErrCaptchaUnsolvable) && !errors.Is(err, ErrTokenExpired) {
+			return res, id, err
+		}
+		c.logf("2captcha: GeeTest challenge rejected, attempt %d of %d: %v", attempt, geeTestRefreshAttempts, err)
+	}
+	return res, id, err
+}
+
+// SolveGeeTestV4 performs a GeeTest v4 solving request to 2captcha.com
+// and returns with the solved captcha and captcha ID if the request was successful.
+// extra is merged into the request for GeeTest v4 deployments requiring
+// additional parameters, it can not override captcha_id and pageurl.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#geetest-v4
+//
+// Deprecated: use the Solve of the solver package with a GeeTestTask of Version 4.
+func (c *TwoCaptchaClient) SolveGeeTestV4(captchaID, siteURL string, extra map[string]string, delay time.Duration, retries int, opts ...SolveOption) (GeeTestV4Result, string, error) {
+	return c.SolveGeeTestV4WithContext(context.Background(), captchaID, siteURL, extra, delay, retries, opts...)
+}
+
+// SolveGeeTestV4WithContext is SolveGeeTestV4 with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a GeeTestTask of Version 4.
+func (c *TwoCaptchaClient) SolveGeeTestV4WithContext(ctx context.Context, captchaID, siteURL string, extra map[string]string, delay time.Duration, retries int, opts ...SolveOption) (GeeTestV4Result, string, error) {
+	params := make(map[string]string, len(extra)+3)
+	for k, v := range extra {
+		params[k] = v
+	}
+	params["captcha_id"] = captchaID
+	params["pageurl"] = siteURL
+	params["method"] = "geetest_v4"
+
+	res, err := c.solve(ctx, params, delay, retries, opts)
+	if err != nil {
+		return GeeTestV4Result{}, res.ID, err
+	}
+	var result GeeTestV4Result
+	if err := json.Unmarshal([]byte(res.Answer), &result); err != nil {
+		return result, res.ID, errors.New("Invalid GeeTest v4 answer: " + res.Answer)
+	}
+	return result, res.ID, nil
+}
@@ -0,0 +1,170 @@
+package twocaptcha
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"strconv"
+	"strings"
+)
+
+// Point is a point of an image in pixels, relative to the top left corner
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// String returns the point in the x=X,y=Y format used by 2captcha
+func (p Point) String() string {
+	return fmt.Sprintf("x=%d,y=%d", p.X, p.Y)
+}
+
+// Polygon is a list of points, e.g. the clicks of a coordinate captcha
+// or the outline of a canvas captcha
+type Polygon []Point
+
+// String returns the polygon in the x=X,y=Y;x=X,y=Y format used by 2captcha
+func (p Polygon) String() string {
+	points := make([]string, len(p))
+	for i, pt := range p {
+		points[i] = pt.String()
+	}
+	return strings.Join(points, ";")
+}
+
+// ParsePoint parses a point in the x=X,y=Y format
+func ParsePoint(s string) (Point, error) {
+	var p Point
+	var hasX, hasY bool
+	for _, field := range strings.Split(s, ",") {
+		kv := strings.SplitN(strings.TrimSpace(field), "=", 2)
+		if len(kv) != 2 {
+			return p, errors.New("Invalid point: " + s)
+		}
+		v, err := strconv.Atoi(kv[1])
+		if err != nil {
+			return p, errors.New("Invalid point: " + s)
+		}
+		switch kv[0] {
+		case "x":
+			p.X, hasX = v, true
+		case "y":
+			p.Y, hasY = v, true
+		}
+	}
+	if !hasX || !hasY {
+		return p, errors.New("Invalid point: " + s)
+	}
+	return p, nil
+}
+
+// ParsePolygon parses a list of points returned by 2captcha, e.g.
+// coordinates:x=39,y=59;x=252,y=72 or [{"x":"39","y":"59"}] in JSONFormat
+func ParsePolygon(s string) (Polygon, error) {
+	if strings.HasPrefix(s, "[") {
+		return parseJSONPolygon(s)
+	}
+	if i := strings.Index(s, ":"); i >= 0 {
+		s = s[i+1:]
+	}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Polygon{}, nil
+	}
+	fields := strings.Split(s, ";")
+	poly := make(Polygon, 0, len(fields))
+	for _, f := range fields {
+		if strings.TrimSpace(f) == "" {
+			continue
+		}
+		p, err := ParsePoint(f)
+		if err != nil {
+			return nil, err
+		}
+		poly = append(poly, p)
+	}
+	return poly, nil
+}
+
+// ParseBoundingBoxes parses the rectangles of a bounding box captcha. They are
+// sent in JSONFormat either as objects, e.g. [{"xMin":1,"yMin":2,"xMax":3,"yMax":4}],
+// or as arrays of the corners, e.g. [[1,2,3,4]], and in the text format as
+// the pairs of the opposite corners, e.g. x=1,y=2;x=3,y=4.
+func ParseBoundingBoxes(s string) ([]image.Rectangle, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "[") {
+		return parseJSONBoundingBoxes(s)
+	}
+	corners, err := ParsePolygon(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(corners)%2 != 0 {
+		return nil, errors.New("Invalid bounding boxes: " + s)
+	}
+	boxes := make([]image.Rectangle, 0, len(corners)/2)
+	for i := 0; i < len(corners); i += 2 {
+		boxes = append(boxes, image.Rect(corners[i].X, corners[i].Y, corners[i+1].X, corners[i+1].Y))
+	}
+	return boxes, nil
+}
+
+// parseJSONBoundingBoxes parses the rectangles of a bounding box captcha in JSONFormat
+func parseJSONBoundingBoxes(s string) ([]image.Rectangle, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal([]byte(s), &raw); err != nil {
+		return nil, errors.New("Invalid bounding boxes: " + s)
+	}
+	boxes := make([]image.Rectangle, len(raw))
+	for i, r := range raw {
+		var corners []json.Number
+		if err := json.Unmarshal(r, &corners); err != nil {
+			var box struct {
+				XMin json.Number `json:"xMin"`
+				YMin json.Number `json:"yMin"`
+				XMax json.Number `json:"xMax"`
+				YMax json.Number `json:"yMax"`
+			}
+			if err := json.Unmarshal(r, &box); err != nil {
+				return nil, errors.New("Invalid bounding boxes: " + s)
+			}
+			corners = []json.Number{box.XMin, box.YMin, box.XMax, box.YMax}
+		}
+		if len(corners) != 4 {
+			return nil, errors.New("Invalid bounding boxes: " + s)
+		}
+		var v [4]int
+		for j, n := range corners {
+			f, err := strconv.ParseFloat(n.String(), 64)
+			if err != nil {
+				return nil, errors.New("Invalid bounding boxes: " + s)
+			}
+			v[j] = int(f)
+		}
+		boxes[i] = image.Rect(v[0], v[1], v[2], v[3])
+	}
+	return boxes, nil
+}
+
+// parseJSONPolygon parses a list of points in JSONFormat. The coordinates
+// are sent either as numbers or as strings.
+func parseJSONPolygon(s string) (Polygon, error) {
+	var points []struct {
+		X json.Number `json:"x"`
+		Y json.Number `json:"y"`
+	}
+	if err := json.Unmarshal([]byte(s), &points); err != nil {
+		return nil, errors.New("Invalid points: " + s)
+	}
+	poly := make(Polygon, len(points))
+	for i, pt := range points {
+		x, errX := strconv.Atoi(pt.X.String())
+		y, errY := strconv.Atoi(pt.Y.String())
+		if errX != nil || errY != nil {
+			return nil, errors.New("Invalid points: " + s)
+		}
+		poly[i] = Point{X: x, Y: y}
+	}
+	return poly, nil
+}
@@ -0,0 +1,100 @@
+package twocaptcha
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GridOptions contains the parameters of a grid captcha solving request
+type GridOptions struct {
+	// Rows is the number of rows of the grid
+	Rows int
+	// Cols is the number of columns of the grid
+	Cols int
+	// TextInstructions tells the worker what to select, e.g. "select all cars"
+	TextInstructions string
+	// MinClicks is the minimum number of cells the worker has to select
+	MinClicks int
+	// MaxClicks is the maximum number of cells the worker can select
+	MaxClicks int
+}
+
+// SolveGrid performs a grid captcha solving request to 2captcha.com
+// and returns with the selected cells and captcha ID if the request was successful.
+// Cells are numbered from 1, left to right, top to bottom. Rows and Cols
+// default to the 3x3 grid of reCAPTCHA if they are not set. An example of
+// the objects to select can be attached with WithInstructionImage.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#grid
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type GridTask.
+func (c *TwoCaptchaClient) SolveGrid(image []byte, opts GridOptions, delay time.Duration, retries int, solveOpts ...SolveOption) ([]int, string, error) {
+	return c.SolveGridWithContext(context.Background(), image, opts, delay, retries, solveOpts...)
+}
+
+// SolveGridWithContext is SolveGrid with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type GridTask.
+func (c *TwoCaptchaClient) SolveGridWithContext(ctx context.Context, image []byte, opts GridOptions, delay time.Duration, retries int, solveOpts ...SolveOption) ([]int, string, error) {
+	if err := validateImage(image); err != nil {
+		return nil, "", err
+	}
+	if opts.Rows < 0 || opts.Cols < 0 {
+		return nil, "", errors.New("Rows and Cols must not be negative")
+	}
+	if opts.MinClicks < 0 || opts.MaxClicks < 0 {
+		return nil, "", errors.New("MinClicks and MaxClicks must not be negative")
+	}
+	if opts.MaxClicks > 0 && opts.MinClicks > opts.MaxClicks {
+		return nil, "", errors.New("MinClicks must not be greater than MaxClicks")
+	}
+	params := map[string]string{
+		"method":    "base64",
+		"body":      base64.StdEncoding.EncodeToString(image),
+		"recaptcha": "1",
+	}
+	if opts.Rows > 0 {
+		params["recaptcharows"] = strconv.Itoa(opts.Rows)
+	}
+	if opts.Cols > 0 {
+		params["recaptchacols"] = strconv.Itoa(opts.Cols)
+	}
+	if opts.TextInstructions != "" {
+		params["textinstructions"] = opts.TextInstructions
+	}
+	if opts.MinClicks > 0 {
+		params["min_clicks"] = strconv.Itoa(opts.MinClicks)
+	}
+	if opts.MaxClicks > 0 {
+		params["max_clicks"] = strconv.Itoa(opts.MaxClicks)
+	}
+	res, err := c.solveProxyless(ctx, params, delay, retries, solveOpts)
+	if err != nil {
+		return nil, res.ID, err
+	}
+	cells, err := parseGridAnswer(res.Answer)
+	return cells, res.ID, err
+}
+
+// parseGridAnswer parses a grid answer in the click:3/6/8 format
+func parseGridAnswer(answer string) ([]int, error) {
+	answer = strings.TrimPrefix(answer, "click:")
+	if answer == "" || answer == "No_matching_images" {
+		return []int{}, nil
+	}
+	fields := strings.Split(answer, "/")
+	cells := make([]int, len(fields))
+	for i, f := range fields {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil {
+			return nil, errors.New("Invalid grid answer: " + answer)
+		}
+		cells[i] = n
+	}
+	return cells, nil
+}
@@ -0,0 +1,60 @@
+package twocaptcha
+
+import (
+	"context"
+	"time"
+)
+
+// HCaptchaOptions contains the optional parameters of an hCaptcha solving request
+type HCaptchaOptions struct {
+	// Invisible marks the captcha as invisible hCaptcha
+	Invisible bool
+	// RQData is the custom rqdata value used by hCaptcha Enterprise.
+	// It is sent as the data parameter and requires UserAgent to be set.
+	RQData string
+	// UserAgent is the user agent the token is bound to. The same
+	// user agent must be used when submitting the token to the site.
+	UserAgent string
+	// Domain is the domain the hCaptcha script is loaded from,
+	// hcaptcha.com or js.hcaptcha.com, e.g. for the Enterprise captchas
+	Domain string
+}
+
+// SolveHCaptcha performs an hCaptcha solving request to 2captcha.com
+// and returns with the solved captcha and captcha ID if the request was successful.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#solving_hcaptcha
+//
+// Deprecated: use the Solve of the solver package with an HCaptchaTask.
+func (c *TwoCaptchaClient) SolveHCaptcha(siteURL, siteKey string, opts HCaptchaOptions, delay time.Duration, retries int, solveOpts ...SolveOption) (string, string, error) {
+	return c.SolveHCaptchaWithContext(context.Background(), siteURL, siteKey, opts, delay, retries, solveOpts...)
+}
+
+// SolveHCaptchaWithContext is SolveHCaptcha with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with an HCaptchaTask.
+func (c *TwoCaptchaClient) SolveHCaptchaWithContext(ctx context.Context, siteURL, siteKey string, opts HCaptchaOptions, delay time.Duration, retries int, solveOpts ...SolveOption) (string, string, error) {
+	task := HCaptchaTask{
+		WebsiteURL:  siteURL,
+		WebsiteKey:  siteKey,
+		IsInvisible: opts.Invisible,
+		UserAgent:   opts.UserAgent,
+	}
+	if opts.RQData != "" {
+		task.EnterprisePayload = map[string]interface{}{"rqdata": opts.RQData}
+	}
+	if task.UserAgent == "" {
+		// WithUserAgent satisfies the rqdata requirement too
+		task.UserAgent = c.solveOptions(solveOpts).userAgent
+	}
+	params, err := taskParams(task)
+	if err != nil {
+		return "", "", err
+	}
+	if opts.Domain != "" {
+		params["domain"] = opts.Domain
+	}
+	res, err := c.solve(ctx, params, delay, retries, solveOpts)
+	return res.Answer, res.ID, err
+}
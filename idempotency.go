@@ -0,0 +1,100 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultIdempotencyWindow is the time a solved captcha blocks the
+// resubmission of its idempotency key
+const DefaultIdempotencyWindow = 10 * time.Minute
+
+// ErrDuplicateTask is returned by the solves with an idempotency key if a
+// captcha with the same key is pending or was solved recently. The ID of the
+// captcha is returned with the error if it is known, its answer can be
+// fetched with Result or WaitResult instead of paying for a new captcha.
+var ErrDuplicateTask = errors.New("Captcha with the same idempotency key is pending or was solved recently")
+
+// idempotencyKey is the context key of WithIdempotencyKey
+type idempotencyKey struct{}
+
+// WithIdempotencyKey sets the idempotency key of the solve, e.g. the ID of
+// the job of the application. The solve fails with ErrDuplicateTask instead
+// of submitting a captcha if a captcha with the same key is being solved by
+// the client, or is recorded in the TaskStore of the client as pending or as
+// solved within the idempotency window. The captchas failed with an error of
+// the API do not block their key, they can be retried. The captchas of timed
+// out solves are still being solved, they stay pending in the TaskStore and
+// block their key until they are recovered.
+//
+// Without a TaskStore only the solves in progress are detected. The check of
+// the TaskStore is not atomic, the processes sharing a store may still submit
+// the same key at the same time.
+func WithIdempotencyKey(key string) SolveOption {
+	return func(o *solveOptions) {
+		o.idempotencyKey = key
+	}
+}
+
+// WithIdempotencyWindow sets the time a solved captcha blocks the
+// resubmission of its idempotency key, DefaultIdempotencyWindow by default.
+// The solved captchas are kept in the TaskStore for the window.
+func WithIdempotencyWindow(d time.Duration) Option {
+	return func(c *TwoCaptchaClient) {
+		c.idempotencyWindow = d
+	}
+}
+
+// idempotency returns the idempotency key of a solve
+func idempotency(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKey{}).(string)
+	return key
+}
+
+// claimKey reserves the idempotency key of a solve until release is called.
+// ErrDuplicateTask is returned with the ID of the existing captcha if the key
+// is taken.
+func (c *TwoCaptchaClient) claimKey(ctx context.Context) (release func(), captchaId string, err error) {
+	key := idempotency(ctx)
+	if key == "" {
+		return func() {}, "", nil
+	}
+	c.mu.Lock()
+	if c.claimed[key] {
+		c.mu.Unlock()
+		return nil, "", ErrDuplicateTask
+	}
+	if c.claimed == nil {
+		c.claimed = make(map[string]bool)
+	}
+	c.claimed[key] = true
+	c.mu.Unlock()
+	release = func() {
+		c.mu.Lock()
+		delete(c.claimed, key)
+		c.mu.Unlock()
+	}
+
+	if c.store == nil {
+		return release, "", nil
+	}
+	tasks, err := c.store.List()
+	if err != nil {
+		release()
+		return nil, "", err
+	}
+	for _, task := range tasks {
+		if task.IdempotencyKey == key && !c.expired(task) {
+			release()
+			return nil, task.ID, ErrDuplicateTask
+		}
+	}
+	return release, "", nil
+}
+
+// expired reports whether a solved captcha kept in the TaskStore for its
+// idempotency key is out of the idempotency window
+func (c *TwoCaptchaClient) expired(task StoredTask) bool {
+	return !task.CompletedAt.IsZero() && time.Since(task.CompletedAt) >= c.idempotencyWindow
+}
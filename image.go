@@ -0,0 +1,212 @@
+package twocaptcha
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const (
+	// minImageSize is the minimum size of a captcha image accepted by 2captcha
+	minImageSize = 100
+	// maxImageSize is the maximum size of a captcha image accepted by 2captcha
+	maxImageSize = 600 * 1024
+)
+
+// ImageOptions contains the optional parameters of a normal captcha solving request
+type ImageOptions struct {
+	// CaseSensitive marks the answer as case sensitive
+	CaseSensitive bool
+	// Numeric restricts the answer: 1 - numbers only, 2 - letters only,
+	// 3 - numbers or letters, 4 - numbers and letters
+	Numeric int
+	// MinLen is the minimum length of the answer
+	MinLen int
+	// MaxLen is the maximum length of the answer
+	MaxLen int
+	// Phrase marks answers containing two or more words
+	Phrase bool
+	// Lang is the language of the captcha, e.g. "en"
+	Lang string
+	// Language restricts the alphabet of the answer: 1 - Cyrillic, 2 - Latin
+	Language int
+	// TextInstructions guides the worker, e.g. "type only the red letters"
+	TextInstructions string
+	// InstructionImage is an image guiding the worker, e.g. an example of
+	// the objects to select
+	InstructionImage []byte
+	// Multipart uploads the image with a multipart method=post request
+	// instead of sending it base64 encoded
+	Multipart bool
+	// Filters are applied to the image before it is uploaded with
+	// FilterImage, e.g. Grayscale() and Resize(600, 600)
+	Filters []ImageFilter
+}
+
+// SolveImageCaptcha performs a normal (image) captcha solving request to 2captcha.com
+// and returns with the text of the captcha if the request was successful.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#solving_normal_captcha
+func (c *TwoCaptchaClient) SolveImageCaptcha(ctx context.Context, image []byte, opts ImageOptions, solveOpts ...SolveOption) (CaptchaResult, error) {
+	if len(opts.Filters) > 0 {
+		filtered, err := FilterImage(image, opts.Filters...)
+		if err != nil {
+			return CaptchaResult{}, err
+		}
+		image = filtered
+	}
+	if err := validateImage(image); err != nil {
+		return CaptchaResult{}, err
+	}
+	params, err := imageParams(opts)
+	if err != nil {
+		return CaptchaResult{}, err
+	}
+	return c.solveCached(image, params, solveOpts, func() (CaptchaResult, error) {
+		return c.solveImage(ctx, image, params, opts, solveOpts)
+	})
+}
+
+// solveImage solves an image captcha with the parameters of opts
+func (c *TwoCaptchaClient) solveImage(ctx context.Context, image []byte, params map[string]string, opts ImageOptions, solveOpts []SolveOption) (CaptchaResult, error) {
+	var files []file
+	if opts.Multipart {
+		params["method"] = "post"
+		files = append(files, file{field: "file", name: "captcha", data: image})
+		if len(opts.InstructionImage) > 0 {
+			delete(params, "imginstructions")
+			files = append(files, file{field: "imginstructions", name: "instructions", data: opts.InstructionImage})
+		}
+	} else {
+		params["method"] = "base64"
+		params["body"] = base64.StdEncoding.EncodeToString(image)
+	}
+
+	delay, retries := c.polling()
+	return c.solveProxyless(ctx, params, delay, retries, solveOpts, files...)
+}
+
+// SolveImageCaptchaReader solves a captcha image read from r with
+// SolveImageCaptcha. The image is streamed with a multipart request without
+// buffering it in memory, e.g. to upload large screenshots. Multipart,
+// InstructionImage and Filters are ignored, the size and the type of the
+// image are validated by 2captcha.
+func (c *TwoCaptchaClient) SolveImageCaptchaReader(ctx context.Context, r io.Reader, opts ImageOptions, solveOpts ...SolveOption) (CaptchaResult, error) {
+	opts.InstructionImage = nil
+	params, err := imageParams(opts)
+	if err != nil {
+		return CaptchaResult{}, err
+	}
+	params["method"] = "post"
+
+	delay, retries := c.polling()
+	return c.solveProxyless(ctx, params, delay, retries, solveOpts, file{field: "file", name: "captcha", reader: r})
+}
+
+// SolveImageCaptchaBase64 solves a base64 encoded captcha image
+// with SolveImageCaptcha. Multipart is ignored, the image is sent as is.
+func (c *TwoCaptchaClient) SolveImageCaptchaBase64(ctx context.Context, image string, opts ImageOptions, solveOpts ...SolveOption) (CaptchaResult, error) {
+	decoded, err := base64.StdEncoding.DecodeString(image)
+	if err != nil {
+		return CaptchaResult{}, err
+	}
+	if err := validateImage(decoded); err != nil {
+		return CaptchaResult{}, err
+	}
+	params, err := imageParams(opts)
+	if err != nil {
+		return CaptchaResult{}, err
+	}
+	return c.solveCached(decoded, params, solveOpts, func() (CaptchaResult, error) {
+		params["method"] = "base64"
+		params["body"] = image
+		delay, retries := c.polling()
+		return c.solveProxyless(ctx, params, delay, retries, solveOpts)
+	})
+}
+
+// imageParams builds the API parameters of the image captcha options
+func imageParams(opts ImageOptions) (map[string]string, error) {
+	if opts.MinLen < 0 || opts.MaxLen < 0 {
+		return nil, errors.New("MinLen and MaxLen must not be negative")
+	}
+	if opts.MaxLen > 0 && opts.MinLen > opts.MaxLen {
+		return nil, errors.New("MinLen must not be greater than MaxLen")
+	}
+	params := map[string]string{}
+	if opts.CaseSensitive {
+		params["regsense"] = "1"
+	}
+	if opts.Numeric > 0 {
+		params["numeric"] = fmt.Sprint(opts.Numeric)
+	}
+	if opts.MinLen > 0 {
+		params["min_len"] = fmt.Sprint(opts.MinLen)
+	}
+	if opts.MaxLen > 0 {
+		params["max_len"] = fmt.Sprint(opts.MaxLen)
+	}
+	if opts.Phrase {
+		params["phrase"] = "1"
+	}
+	if opts.Lang != "" {
+		params["lang"] = opts.Lang
+	}
+	if opts.Language > 0 {
+		params["language"] = fmt.Sprint(opts.Language)
+	}
+	if opts.TextInstructions != "" {
+		params["textinstructions"] = opts.TextInstructions
+	}
+	if len(opts.InstructionImage) > 0 {
+		params["imginstructions"] = base64.StdEncoding.EncodeToString(opts.InstructionImage)
+	}
+	return params, nil
+}
+
+// SolveImageCaptchaFromURL downloads the captcha image from imgURL and solves it
+// with SolveImageCaptcha. The image is fetched with the HTTP client of the
+// TwoCaptchaClient, header is added to the download request, e.g. to pass
+// the session cookies required by the site.
+func (c *TwoCaptchaClient) SolveImageCaptchaFromURL(ctx context.Context, imgURL string, header http.Header, opts ImageOptions, solveOpts ...SolveOption) (CaptchaResult, error) {
+	req, err := http.NewRequest("GET", imgURL, nil)
+	if err != nil {
+		return CaptchaResult{}, err
+	}
+	req = req.WithContext(ctx)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := c.httpDo(req)
+	if err != nil {
+		return CaptchaResult{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return CaptchaResult{}, fmt.Errorf("Failed to download captcha image: %s", resp.Status)
+	}
+	image, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxImageSize+1))
+	if err != nil {
+		return CaptchaResult{}, err
+	}
+	return c.SolveImageCaptcha(ctx, image, opts, solveOpts...)
+}
+
+// validateImage checks the size and the type of a captcha image
+func validateImage(image []byte) error {
+	if len(image) < minImageSize {
+		return errors.New("Captcha image is too small")
+	}
+	if len(image) > maxImageSize {
+		return errors.New("Captcha image is too large")
+	}
+	if ct := http.DetectContentType(image); !strings.HasPrefix(ct, "image/") {
+		return errors.New("Unsupported captcha image type: " + ct)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package twocaptcha
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // decode GIF captchas
+	"image/jpeg"
+	"image/png"
+)
+
+// ImageFilter transforms a captcha image before it is uploaded, see
+// ImageOptions.Filters and FilterImage
+type ImageFilter interface {
+	Filter(img image.Image) image.Image
+}
+
+// ImageFilterFunc adapts a function to the ImageFilter interface
+type ImageFilterFunc func(img image.Image) image.Image
+
+// Filter calls f(img)
+func (f ImageFilterFunc) Filter(img image.Image) image.Image {
+	return f(img)
+}
+
+// FilterImage decodes a GIF, JPEG or PNG image, applies the filters in order
+// and encodes the result as PNG, or as JPEG if the PNG would exceed the
+// size limit of 2captcha
+func FilterImage(data []byte, filters ...ImageFilter) ([]byte, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("Invalid captcha image: " + err.Error())
+	}
+	for _, f := range filters {
+		img = f.Filter(img)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	if buf.Len() <= maxImageSize {
+		return buf.Bytes(), nil
+	}
+	buf.Reset()
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Grayscale converts the image to shades of gray
+func Grayscale() ImageFilter {
+	return ImageFilterFunc(func(img image.Image) image.Image {
+		gray := image.NewGray(img.Bounds())
+		draw.Draw(gray, gray.Bounds(), img, img.Bounds().Min, draw.Src)
+		return gray
+	})
+}
+
+// Threshold converts the image to black and white, the pixels lighter than
+// level become white, the others black. It removes the light noise of the
+// background of text captchas.
+func Threshold(level uint8) ImageFilter {
+	return ImageFilterFunc(func(img image.Image) image.Image {
+		b := img.Bounds()
+		bw := image.NewGray(b)
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				if color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y > level {
+					bw.SetGray(x, y, color.Gray{Y: 255})
+				}
+			}
+		}
+		return bw
+	})
+}
+
+// Crop cuts r out of the image, e.g. to remove the surroundings of the
+// captcha from a screenshot
+func Crop(r image.Rectangle) ImageFilter {
+	return ImageFilterFunc(func(img image.Image) image.Image {
+		r := r.Intersect(img.Bounds())
+		cropped := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+		draw.Draw(cropped, cropped.Bounds(), img, r.Min, draw.Src)
+		return cropped
+	})
+}
+
+// Resize shrinks the image to fit within maxWidth x maxHeight keeping its
+// aspect ratio, e.g. Resize(600, 600) for the size limit of 2captcha.
+// Smaller images are not enlarged. A zero limit is not applied.
+func Resize(maxWidth, maxHeight int) ImageFilter {
+	return ImageFilterFunc(func(img image.Image) image.Image {
+		b := img.Bounds()
+		scale := 1.0
+		if maxWidth > 0 && b.Dx() > maxWidth {
+			scale = float64(maxWidth) / float64(b.Dx())
+		}
+		if maxHeight > 0 && float64(b.Dy())*scale > float64(maxHeight) {
+			scale = float64(maxHeight) / float64(b.Dy())
+		}
+		if scale == 1 {
+			return img
+		}
+		w, h := int(float64(b.Dx())*scale), int(float64(b.Dy())*scale)
+		if w < 1 {
+			w = 1
+		}
+		if h < 1 {
+			h = 1
+		}
+		resized := image.NewRGBA(image.Rect(0, 0, w, h))
+		for y := 0; y < h; y++ {
+			y0, y1 := b.Min.Y+y*b.Dy()/h, b.Min.Y+(y+1)*b.Dy()/h
+			for x := 0; x < w; x++ {
+				x0, x1 := b.Min.X+x*b.Dx()/w, b.Min.X+(x+1)*b.Dx()/w
+				resized.Set(x, y, average(img, image.Rect(x0, y0, x1, y1)))
+			}
+		}
+		return resized
+	})
+}
+
+// average returns the average color of the pixels of r, r is not empty
+func average(img image.Image, r image.Rectangle) color.Color {
+	var sr, sg, sb, sa, n uint64
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			sr, sg, sb, sa = sr+uint64(r), sg+uint64(g), sb+uint64(b), sa+uint64(a)
+			n++
+		}
+	}
+	return color.RGBA64{R: uint16(sr / n), G: uint16(sg / n), B: uint16(sb / n), A: uint16(sa / n)}
+}
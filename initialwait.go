@@ -0,0 +1,56 @@
+package twocaptcha
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultInitialWait is the wait before the first poll of the captcha types
+// without a default of their own
+const DefaultInitialWait = 10 * time.Second
+
+// initialWaits are the waits before the first poll of the in.php methods
+// solved faster or slower than usual
+var initialWaits = map[string]time.Duration{
+	"base64":        5 * time.Second,
+	"post":          5 * time.Second,
+	"textcaptcha":   5 * time.Second,
+	"audio":         5 * time.Second,
+	"rotatecaptcha": 5 * time.Second,
+	"userrecaptcha": 15 * time.Second,
+	"funcaptcha":    20 * time.Second,
+
+	// JSON API v2 task types
+	"ImageToTextTask":          5 * time.Second,
+	"RecaptchaV2Task":          15 * time.Second,
+	"RecaptchaV2TaskProxyless": 15 * time.Second,
+	"RecaptchaV3TaskProxyless": 15 * time.Second,
+	"FunCaptchaTask":           20 * time.Second,
+	"FunCaptchaTaskProxyless":  20 * time.Second,
+}
+
+// initialWaitKey is the context key of the initial wait of a single solve
+type initialWaitKey struct{}
+
+// InitialWait returns the default wait between the submission of a captcha
+// with the in.php method or the JSON API v2 task type and its first poll:
+// 5 seconds for the image, text and audio captchas, 15 seconds for
+// reCAPTCHA, 20 seconds for FunCaptcha and DefaultInitialWait otherwise.
+// Use WithInitialWait to override it.
+func InitialWait(method string) time.Duration {
+	if d, ok := initialWaits[method]; ok {
+		return d
+	}
+	return DefaultInitialWait
+}
+
+// initialWait returns the wait before the first poll of a captcha of a solve
+func (c *TwoCaptchaClient) initialWait(ctx context.Context, method string) time.Duration {
+	if c.sandbox {
+		return 0
+	}
+	if d, ok := ctx.Value(initialWaitKey{}).(time.Duration); ok {
+		return d
+	}
+	return InitialWait(method)
+}
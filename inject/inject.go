@@ -0,0 +1,160 @@
+// Package inject builds the JavaScript injecting solved captcha tokens into
+// a page opened by a browser automation library. The scripts are plain
+// strings, they are evaluated with any library, e.g. with chromedp:
+//
+//	chromedp.Run(ctx, chromedp.Evaluate(inject.Recaptcha(token), nil))
+//
+// or with rod:
+//
+//	page.MustEval(inject.Function(inject.Recaptcha(token)))
+//
+// The scripts set the response fields of the widgets and call their
+// callbacks, so the page behaves as if the captcha was solved by the user.
+package inject
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// recaptchaScript sets the g-recaptcha-response fields and calls the callback
+// of the widget, either set with data-callback or passed to grecaptcha.render
+// and kept in ___grecaptcha_cfg
+const recaptchaScript = `(function(token) {
+	document.querySelectorAll('[name="g-recaptcha-response"], [id^="g-recaptcha-response"]').forEach(function(el) {
+		el.value = token;
+		el.innerHTML = token;
+	});
+	var called = false;
+	document.querySelectorAll('.g-recaptcha[data-callback]').forEach(function(el) {
+		var cb = window[el.getAttribute('data-callback')];
+		if (typeof cb === 'function') {
+			cb(token);
+			called = true;
+		}
+	});
+	if (called || typeof ___grecaptcha_cfg === 'undefined') {
+		return called;
+	}
+	var seen = [];
+	function find(obj, depth) {
+		if (!obj || typeof obj !== 'object' || depth > 4 || seen.indexOf(obj) >= 0) {
+			return;
+		}
+		seen.push(obj);
+		for (var key in obj) {
+			var v;
+			try { v = obj[key]; } catch (e) { continue; }
+			if (key === 'callback') {
+				if (typeof v === 'function') {
+					v(token);
+					called = true;
+				} else if (typeof v === 'string' && typeof window[v] === 'function') {
+					window[v](token);
+					called = true;
+				}
+			} else {
+				find(v, depth + 1);
+			}
+		}
+	}
+	find(___grecaptcha_cfg.clients, 0);
+	return called;
+})(%s)`
+
+// widgetScript sets the response fields of a widget and calls its data-callback
+const widgetScript = `(function(token, fields, widget) {
+	document.querySelectorAll(fields).forEach(function(el) {
+		el.value = token;
+		el.innerHTML = token;
+	});
+	var called = false;
+	document.querySelectorAll(widget + '[data-callback]').forEach(function(el) {
+		var cb = window[el.getAttribute('data-callback')];
+		if (typeof cb === 'function') {
+			cb(token);
+			called = true;
+		}
+	});
+	return called;
+})(%s, %s, %s)`
+
+// Recaptcha returns the script injecting a reCAPTCHA v2 or v3 token.
+// The script evaluates to true if a callback of the widget was called.
+func Recaptcha(token string) string {
+	return sprintf(recaptchaScript, token)
+}
+
+// HCaptcha returns the script injecting an hCaptcha token.
+// The script evaluates to true if a callback of the widget was called.
+func HCaptcha(token string) string {
+	return sprintf(widgetScript, token, `[name="h-captcha-response"], [name="g-recaptcha-response"]`, ".h-captcha")
+}
+
+// Turnstile returns the script injecting a Cloudflare Turnstile token.
+// The script evaluates to true if a callback of the widget was called.
+func Turnstile(token string) string {
+	return sprintf(widgetScript, token, `[name="cf-turnstile-response"], [name="g-recaptcha-response"]`, ".cf-turnstile")
+}
+
+// Function wraps a script into a function expression, for the libraries
+// evaluating functions instead of expressions, e.g. rod
+func Function(script string) string {
+	return "() => " + script
+}
+
+// sprintf formats script with the JavaScript string literals of args
+func sprintf(script string, args ...string) string {
+	literals := make([]interface{}, len(args))
+	for i, arg := range args {
+		// a JSON string is a valid JavaScript string literal
+		b, _ := json.Marshal(arg)
+		literals[i] = string(b)
+	}
+	return fmt.Sprintf(script, literals...)
+}
+
+// TurnstileInterceptor is the script capturing the parameters passed to
+// turnstile.render, e.g. on Cloudflare Challenge pages. It must be evaluated
+// before the scripts of the page, e.g. with
+// page.AddScriptToEvaluateOnNewDocument of chromedp. The parameters are read
+// with TurnstileParams.
+const TurnstileInterceptor = `(function() {
+	var timer = setInterval(function() {
+		if (!window.turnstile || window.turnstile.__twocaptcha) {
+			return;
+		}
+		clearInterval(timer);
+		var render = window.turnstile.render;
+		window.turnstile.render = function(container, params) {
+			window.__twocaptchaTurnstile = {
+				sitekey: params.sitekey,
+				action: params.action || '',
+				cData: params.cData || '',
+				chlPageData: params.chlPageData || '',
+				userAgent: navigator.userAgent
+			};
+			window.__twocaptchaTurnstileCallback = params.callback;
+			return render.apply(this, arguments);
+		};
+		window.turnstile.__twocaptcha = true;
+	}, 10);
+})()`
+
+// TurnstileParams is the script evaluating to the JSON of the parameters
+// captured by TurnstileInterceptor, null if turnstile.render was not called
+// yet. The JSON is parsed with twocaptcha.ParseCloudflareChallenge.
+const TurnstileParams = `(window.__twocaptchaTurnstile ? JSON.stringify(window.__twocaptchaTurnstile) : null)`
+
+// TurnstileCallback returns the script passing a Turnstile token to the
+// callback captured by TurnstileInterceptor. The script evaluates to true if
+// the callback was called.
+func TurnstileCallback(token string) string {
+	return sprintf(`(function(token) {
+	if (typeof window.__twocaptchaTurnstileCallback !== 'function') {
+		return false;
+	}
+	window.__twocaptchaTurnstileCallback(token);
+	return true;
+})(%s)`, token)
+}
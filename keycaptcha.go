@@ -0,0 +1,39 @@
+package twocaptcha
+
+import (
+	"context"
+	"time"
+)
+
+// SolveKeyCaptcha performs a KeyCaptcha solving request to 2captcha.com
+// and returns with the solved captcha and captcha ID if the request was successful.
+// The parameters are the s_s_c_* values of the KeyCaptcha widget on the page.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#solving_keycaptcha
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type KeyCaptchaTaskProxyless.
+func (c *TwoCaptchaClient) SolveKeyCaptcha(userID, sessionID, webServerSign, webServerSign2, siteURL string, delay time.Duration, retries int, opts ...SolveOption) (string, string, error) {
+	return c.SolveKeyCaptchaWithContext(context.Background(), userID, sessionID, webServerSign, webServerSign2, siteURL, delay, retries, opts...)
+}
+
+// SolveKeyCaptchaWithContext is SolveKeyCaptcha with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type KeyCaptchaTaskProxyless.
+func (c *TwoCaptchaClient) SolveKeyCaptchaWithContext(ctx context.Context, userID, sessionID, webServerSign, webServerSign2, siteURL string, delay time.Duration, retries int, opts ...SolveOption) (string, string, error) {
+	res, err := c.solve(
+		ctx,
+		map[string]string{
+			"s_s_c_user_id":          userID,
+			"s_s_c_session_id":       sessionID,
+			"s_s_c_web_server_sign":  webServerSign,
+			"s_s_c_web_server_sign2": webServerSign2,
+			"pageurl":                siteURL,
+			"method":                 "keycaptcha",
+		},
+		delay,
+		retries,
+		opts,
+	)
+	return res.Answer, res.ID, err
+}
@@ -0,0 +1,132 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// keyRetryAfter is the time a failed key of the pool is skipped for,
+// e.g. until the balance of its account is topped up
+const keyRetryAfter = 10 * time.Minute
+
+// keyKey is the context key of the API key used by a solve
+type keyKey struct{}
+
+// WithAPIKeys configures a pool of API keys of different accounts. The
+// captchas are submitted with the first key until it fails with
+// ErrZeroBalance, ErrWrongUserKey or ErrIPNotAllowed, then with the next
+// key. A failed key is used again after 10 minutes. ApiKey is set to the
+// first key, it is used by the other API calls, e.g. reports and GetBalance.
+//
+// The key of a solve is returned in CaptchaResult.APIKey. The captchas are
+// polled with the key they were submitted with. Submit does not fail over.
+func WithAPIKeys(keys ...string) Option {
+	return func(c *TwoCaptchaClient) {
+		if len(keys) == 0 {
+			return
+		}
+		c.ApiKey = keys[0]
+		c.keys = keys
+	}
+}
+
+// key returns the API key of a request
+func (c *TwoCaptchaClient) key(ctx context.Context) string {
+	if key, ok := ctx.Value(keyKey{}).(string); ok {
+		return key
+	}
+	return c.ApiKey
+}
+
+// withKey returns ctx sending the requests with key
+func withKey(ctx context.Context, key string) context.Context {
+	if key == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, keyKey{}, key)
+}
+
+// failover submits a captcha with the keys of the pool until one of them
+// is accepted and returns the context of the solve using the key.
+// rewindable is false if the captcha can not be submitted twice, e.g. an
+// upload from a reader, then the captcha is submitted once.
+func (c *TwoCaptchaClient) failover(ctx context.Context, rewindable bool, submit func(ctx context.Context) (string, error)) (context.Context, string, error) {
+	if len(c.keys) == 0 {
+		id, err := submit(ctx)
+		return ctx, id, err
+	}
+	tried := make(map[string]bool)
+	for {
+		key := c.nextKey(tried)
+		tried[key] = true
+		keyCtx := withKey(ctx, key)
+		id, err := submit(keyCtx)
+		if err == nil || !keyFailed(err) {
+			return keyCtx, id, err
+		}
+		c.mu.Lock()
+		if c.failedKeys == nil {
+			c.failedKeys = make(map[string]time.Time)
+		}
+		c.failedKeys[key] = time.Now()
+		c.mu.Unlock()
+		c.logf("2captcha: API key %d of %d failed: %v", c.keyIndex(key)+1, len(c.keys), err)
+		if !rewindable || len(tried) == len(c.keys) {
+			return keyCtx, id, err
+		}
+	}
+}
+
+// nextKey returns the first key of the pool which did not fail recently
+// and was not tried yet, the first untried key if all of them failed
+func (c *TwoCaptchaClient) nextKey(tried map[string]bool) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fallback := ""
+	for _, key := range c.keys {
+		if tried[key] {
+			continue
+		}
+		if failed, ok := c.failedKeys[key]; !ok || time.Since(failed) >= keyRetryAfter {
+			return key
+		}
+		if fallback == "" {
+			fallback = key
+		}
+	}
+	return fallback
+}
+
+// poolKey returns the key of a solve if the client has a pool of API keys
+func (c *TwoCaptchaClient) poolKey(ctx context.Context) string {
+	if len(c.keys) == 0 {
+		return ""
+	}
+	return c.key(ctx)
+}
+
+// keyIndex returns the index of key in the pool
+func (c *TwoCaptchaClient) keyIndex(key string) int {
+	for i, k := range c.keys {
+		if k == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// rewindable reports whether the files of a captcha can be uploaded again
+func rewindable(files []file) bool {
+	for _, f := range files {
+		if f.reader != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// keyFailed reports whether err is caused by the account of the API key
+func keyFailed(err error) bool {
+	return errors.Is(err, ErrZeroBalance) || errors.Is(err, ErrWrongUserKey) || errors.Is(err, ErrIPNotAllowed)
+}
@@ -0,0 +1,51 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// LeminResult is a solved Lemin Cropped captcha
+type LeminResult struct {
+	Answer      string `json:"answer"`
+	ChallengeID string `json:"challenge_id"`
+}
+
+// SolveLemin performs a Lemin Cropped captcha solving request to 2captcha.com
+// and returns with the solved captcha and captcha ID if the request was successful.
+// captchaID is the ID of the captcha on the page, divID the id of its container.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#lemin
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type LeminTaskProxyless.
+func (c *TwoCaptchaClient) SolveLemin(captchaID, divID, siteURL string, delay time.Duration, retries int, opts ...SolveOption) (LeminResult, string, error) {
+	return c.SolveLeminWithContext(context.Background(), captchaID, divID, siteURL, delay, retries, opts...)
+}
+
+// SolveLeminWithContext is SolveLemin with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type LeminTaskProxyless.
+func (c *TwoCaptchaClient) SolveLeminWithContext(ctx context.Context, captchaID, divID, siteURL string, delay time.Duration, retries int, opts ...SolveOption) (LeminResult, string, error) {
+	res, err := c.solve(
+		ctx,
+		map[string]string{
+			"captcha_id": captchaID,
+			"div_id":     divID,
+			"pageurl":    siteURL,
+			"method":     "lemin",
+		},
+		delay,
+		retries,
+		opts,
+	)
+	if err != nil {
+		return LeminResult{}, res.ID, err
+	}
+	var result LeminResult
+	if err := parseObjectAnswer(res.Answer, &result); err != nil {
+		return result, res.ID, errors.New("Invalid Lemin answer: " + res.Answer)
+	}
+	return result, res.ID, nil
+}
@@ -0,0 +1,132 @@
+package twocaptcha
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// maxLoggedValue is the length of the logged parameter values and responses,
+// longer ones, e.g. base64 images, are truncated
+const maxLoggedValue = 200
+
+// Logger logs the activity of the client, *log.Logger implements it
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// logf logs a message if the client has a Logger
+func (c *TwoCaptchaClient) logf(format string, v ...interface{}) {
+	if c.Logger != nil {
+		c.Logger.Printf(format, v...)
+	}
+}
+
+// debugf logs a message if the client has a Logger and Debug is enabled
+func (c *TwoCaptchaClient) debugf(format string, v ...interface{}) {
+	if c.Debug {
+		c.logf(format, v...)
+	}
+}
+
+// redact formats the parameters of a request for logging without the API key
+func redact(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := params[k]
+		switch k {
+		case "key":
+			v = "REDACTED"
+		case "proxy":
+			v = redactProxy(v)
+		case "pingback":
+			v = redactPingback(v)
+		}
+		fields = append(fields, k+"="+truncate(v))
+	}
+	return strings.Join(fields, " ")
+}
+
+// redactURL returns u without the API key of its query
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	if query.Get("key") == "" {
+		return u.String()
+	}
+	query.Set("key", "REDACTED")
+	r := *u
+	r.RawQuery = query.Encode()
+	return r.String()
+}
+
+// redactProxy removes the credentials of a login:password@host:port proxy
+func redactProxy(proxy string) string {
+	if i := strings.LastIndex(proxy, "@"); i >= 0 {
+		return "REDACTED" + proxy[i:]
+	}
+	return proxy
+}
+
+// redactPingback removes the token of a signed pingback URL, the pingbacks
+// could be forged with it
+func redactPingback(pingback string) string {
+	u, err := url.Parse(pingback)
+	if err != nil {
+		return "REDACTED"
+	}
+	query := u.Query()
+	if query.Get("token") == "" {
+		return pingback
+	}
+	query.Set("token", "REDACTED")
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// redactJSON removes the API key and the proxy credentials from the body of
+// a JSON API v2 request
+func redactJSON(body []byte) string {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "REDACTED"
+	}
+	if _, ok := payload["clientKey"]; ok {
+		payload["clientKey"] = "REDACTED"
+	}
+	if task, ok := payload["task"].(map[string]interface{}); ok {
+		for _, k := range []string{"proxyLogin", "proxyPassword"} {
+			if _, ok := task[k]; ok {
+				task[k] = "REDACTED"
+			}
+		}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "REDACTED"
+	}
+	return string(data)
+}
+
+// redactKey removes the API keys of the client from s
+func (c *TwoCaptchaClient) redactKey(s string) string {
+	for _, key := range append([]string{c.ApiKey}, c.keys...) {
+		if key != "" {
+			s = strings.Replace(s, key, "REDACTED", -1)
+		}
+	}
+	return s
+}
+
+// truncate shortens s to maxLoggedValue characters
+func truncate(s string) string {
+	if len(s) > maxLoggedValue {
+		return s[:maxLoggedValue] + "..."
+	}
+	return s
+}
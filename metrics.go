@@ -0,0 +1,87 @@
+package twocaptcha
+
+import (
+	"context"
+	"time"
+)
+
+// Metrics receives the measurements of the solves of the client, e.g. to
+// export them as Prometheus or OpenTelemetry counters and histograms.
+// method is the in.php method or the task type of the captcha.
+// The functions are called from the goroutines of the solves.
+type Metrics interface {
+	// Submitted is called when a captcha was accepted by 2captcha
+	Submitted(method string)
+	// Solved is called when a captcha was solved with the time between its
+	// submission and its answer and its cost in USD, zero if not reported
+	Solved(method string, latency time.Duration, cost float64)
+	// Failed is called when a captcha could not be submitted or solved with
+	// the API error code or a generic code, see ErrorCode
+	Failed(method string, code string)
+}
+
+// TaggedMetrics is implemented by the Metrics receiving the tags of the
+// solves set with WithTag, its methods are called instead of the methods of
+// Metrics. tags is nil for the solves without tags, it must not be modified.
+type TaggedMetrics interface {
+	Metrics
+	SubmittedTagged(method string, tags map[string]string)
+	SolvedTagged(method string, latency time.Duration, cost float64, tags map[string]string)
+	FailedTagged(method string, code string, tags map[string]string)
+}
+
+// ErrorCode returns the code of an error returned by the client for metrics
+// and logs: the code of an APIError, ERROR_TIMEOUT, ERROR_CANCELED,
+// ERROR_NETWORK or ERROR_OTHER.
+func ErrorCode(err error) string {
+	switch e := err.(type) {
+	case *APIError:
+		return e.Code
+	case *transientError:
+		return "ERROR_NETWORK"
+	case *TimeoutError:
+		return "ERROR_TIMEOUT"
+	}
+	switch err {
+	case ErrTimeout, context.DeadlineExceeded:
+		return "ERROR_TIMEOUT"
+	case context.Canceled, ErrShutdown:
+		return "ERROR_CANCELED"
+	}
+	return "ERROR_OTHER"
+}
+
+// captchaMethod returns the in.php method of a captcha for metrics
+func captchaMethod(params map[string]string) string {
+	if _, ok := params["textcaptcha"]; ok {
+		return "textcaptcha"
+	}
+	return params["method"]
+}
+
+func (c *TwoCaptchaClient) metricSubmitted(ctx context.Context, method string) {
+	if m, ok := c.Metrics.(TaggedMetrics); ok {
+		m.SubmittedTagged(method, tags(ctx))
+	} else if c.Metrics != nil {
+		c.Metrics.Submitted(method)
+	}
+}
+
+func (c *TwoCaptchaClient) metricSolved(ctx context.Context, method string, res CaptchaResult) {
+	if m, ok := c.Metrics.(TaggedMetrics); ok {
+		m.SolvedTagged(method, res.SolveDuration, res.Cost, tags(ctx))
+	} else if c.Metrics != nil {
+		c.Metrics.Solved(method, res.SolveDuration, res.Cost)
+	}
+}
+
+func (c *TwoCaptchaClient) metricFailed(ctx context.Context, method string, err error) {
+	if _, dryRun := err.(*DryRunError); dryRun {
+		return
+	}
+	if m, ok := c.Metrics.(TaggedMetrics); ok {
+		m.FailedTagged(method, ErrorCode(err), tags(ctx))
+	} else if c.Metrics != nil {
+		c.Metrics.Failed(method, ErrorCode(err))
+	}
+}
@@ -0,0 +1,35 @@
+package twocaptcha
+
+import (
+	"context"
+	"time"
+)
+
+// SolveMTCaptcha performs an MTCaptcha solving request to 2captcha.com
+// and returns with the solved captcha and captcha ID if the request was successful.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#mtcaptcha
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type MtCaptchaTaskProxyless.
+func (c *TwoCaptchaClient) SolveMTCaptcha(siteKey, siteURL string, delay time.Duration, retries int, opts ...SolveOption) (string, string, error) {
+	return c.SolveMTCaptchaWithContext(context.Background(), siteKey, siteURL, delay, retries, opts...)
+}
+
+// SolveMTCaptchaWithContext is SolveMTCaptcha with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type MtCaptchaTaskProxyless.
+func (c *TwoCaptchaClient) SolveMTCaptchaWithContext(ctx context.Context, siteKey, siteURL string, delay time.Duration, retries int, opts ...SolveOption) (string, string, error) {
+	res, err := c.solve(
+		ctx,
+		map[string]string{
+			"sitekey": siteKey,
+			"pageurl": siteURL,
+			"method":  "mt_captcha",
+		},
+		delay,
+		retries,
+		opts,
+	)
+	return res.Answer, res.ID, err
+}
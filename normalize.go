@@ -0,0 +1,78 @@
+package twocaptcha
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// textMethods are the in.php methods and the JSON API v2 task types whose
+// answers are typed by the workers
+var textMethods = map[string]bool{
+	"post":            true,
+	"base64":          true,
+	"textcaptcha":     true,
+	"ImageToTextTask": true,
+}
+
+// WithRawAnswers disables the normalization of the text answers, the answers
+// of the image and text captchas are returned as sent by the API.
+// See NormalizeAnswer.
+func WithRawAnswers() Option {
+	return func(c *TwoCaptchaClient) {
+		c.rawAnswers = true
+	}
+}
+
+// NormalizeAnswer normalizes the answer of an image or text captcha typed by
+// a worker. Answers which are not valid UTF-8 are decoded as Windows-1252,
+// HTML entities are decoded, the byte order marks, zero width characters and
+// the surrounding whitespace are removed.
+// The clients normalize the text answers unless WithRawAnswers is set.
+func NormalizeAnswer(answer string) string {
+	if !utf8.ValidString(answer) {
+		answer = decodeWindows1252(answer)
+	}
+	if strings.IndexByte(answer, '&') >= 0 {
+		answer = html.UnescapeString(answer)
+	}
+	answer = strings.Map(func(r rune) rune {
+		switch r {
+		case '\u200b', '\u200c', '\u200d', '\u2060', '\ufeff':
+			return -1
+		}
+		return r
+	}, answer)
+	return strings.TrimFunc(answer, unicode.IsSpace)
+}
+
+// normalizeAnswer normalizes the answer of a solved text captcha
+func (c *TwoCaptchaClient) normalizeAnswer(method string, res *CaptchaResult) {
+	if c.rawAnswers || !textMethods[method] {
+		return
+	}
+	res.Answer = NormalizeAnswer(res.Answer)
+}
+
+// windows1252 maps the bytes 0x80-0x9f of Windows-1252, the other bytes
+// match their Unicode code points
+var windows1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8d, 'Ž', 0x8f,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9d, 'ž', 'Ÿ',
+}
+
+// decodeWindows1252 decodes a Windows-1252 string
+func decodeWindows1252(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) * 2)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 0x80 && c < 0xa0 {
+			b.WriteRune(windows1252[c-0x80])
+		} else {
+			b.WriteRune(rune(c))
+		}
+	}
+	return b.String()
+}
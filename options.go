@@ -0,0 +1,308 @@
+package twocaptcha
+
+import (
+	"context"
+	"encoding/base64"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SolveOption configures a single solve of a captcha
+type SolveOption func(*solveOptions)
+
+// solveOptions contains the options of a single solve
+type solveOptions struct {
+	normalization  PageURLNormalization
+	softID         string
+	lang           string
+	language       int
+	headerACAO     bool
+	proxy          *Proxy
+	format         *ResultFormat
+	pingback       *PingbackServer
+	userAgent      string
+	cookies        map[string]string
+	dataS          string
+	instructions   []byte
+	timeout        time.Duration
+	pollInterval   time.Duration
+	initialWait    *time.Duration
+	priority       *Priority
+	noQueue        bool
+	idempotencyKey string
+	extra          map[string]string
+	transcript     TranscriptNormalization
+	tags           map[string]string
+}
+
+// PageURLNormalization is a set of transformations applied to the page URL
+// before it is sent to 2captcha. Flags can be combined, e.g.
+// PageURLAddScheme | PageURLStripQuery
+type PageURLNormalization int
+
+const (
+	// PageURLAsIs sends the page URL unchanged, this is the default
+	PageURLAsIs PageURLNormalization = 0
+	// PageURLAddScheme prepends https:// to page URLs without a scheme
+	PageURLAddScheme PageURLNormalization = 1
+	// PageURLStripQuery removes the query string and the fragment of the page URL
+	PageURLStripQuery PageURLNormalization = 2
+)
+
+// WithPageURLNormalization sets how the page URL is normalized before it is
+// sent to 2captcha. Captcha providers are sensitive to the format of the page
+// URL in different ways, it should match what the widget on the page expects.
+func WithPageURLNormalization(n PageURLNormalization) SolveOption {
+	return func(o *solveOptions) {
+		o.normalization = n
+	}
+}
+
+// WithSoftID sets the soft_id of the solve, overriding ClientProfile.SoftID
+func WithSoftID(softID string) SolveOption {
+	return func(o *solveOptions) {
+		o.softID = softID
+	}
+}
+
+// WithLang sets the language of the workers solving the captcha,
+// overriding ClientProfile.DefaultLang
+func WithLang(lang string) SolveOption {
+	return func(o *solveOptions) {
+		o.lang = lang
+	}
+}
+
+// WithLanguage restricts the alphabet of the answer of a text or image
+// captcha: 1 - Cyrillic, 2 - Latin. It routes the captchas in non-Latin
+// scripts to the workers who can read them. It overrides ImageOptions.Language.
+func WithLanguage(language int) SolveOption {
+	return func(o *solveOptions) {
+		o.language = language
+	}
+}
+
+// WithHeaderACAO makes the API send the Access-Control-Allow-Origin: *
+// header, e.g. for the browser based pingback flows. It is
+// ClientProfile.HeaderACAO for a single solve.
+func WithHeaderACAO() SolveOption {
+	return func(o *solveOptions) {
+		o.headerACAO = true
+	}
+}
+
+// WithProxy sets the proxy of the solve, overriding the Proxy of the client
+func WithProxy(proxy *Proxy) SolveOption {
+	return func(o *solveOptions) {
+		o.proxy = proxy
+	}
+}
+
+// WithResultFormat sets the response format of the solve,
+// overriding the ResultFormat of the client
+func WithResultFormat(f ResultFormat) SolveOption {
+	return func(o *solveOptions) {
+		o.format = &f
+	}
+}
+
+// WithUserAgent sets the user agent used by the worker to solve the captcha.
+// Tokens of some captcha types are only accepted with the same user agent.
+func WithUserAgent(userAgent string) SolveOption {
+	return func(o *solveOptions) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithCookies sets the cookies used by the worker to solve the captcha
+func WithCookies(cookies map[string]string) SolveOption {
+	return func(o *solveOptions) {
+		o.cookies = cookies
+	}
+}
+
+// WithDataS sets the data-s value of a reCAPTCHA v2 on Google services, e.g.
+// Google Search, for SolveRecaptchaV2. Their tokens are rejected if the
+// captcha was solved without it. It overrides RecaptchaOptions.DataS.
+func WithDataS(dataS string) SolveOption {
+	return func(o *solveOptions) {
+		o.dataS = dataS
+	}
+}
+
+// WithInstructionImage attaches an image showing the worker what to do, e.g.
+// an example of the objects to select, to a grid, canvas or coordinates
+// captcha. Some custom captchas can only be solved with it. Use
+// ImageOptions.InstructionImage for the image captchas.
+func WithInstructionImage(image []byte) SolveOption {
+	return func(o *solveOptions) {
+		o.instructions = image
+	}
+}
+
+// WithTimeout limits the duration of the solve, including the polls, regardless
+// of the polling interval and retries. ErrTimeout is returned together with
+// the captcha ID when it elapses, the captcha can still be reported or its
+// answer collected later.
+func WithTimeout(d time.Duration) SolveOption {
+	return func(o *solveOptions) {
+		o.timeout = d
+	}
+}
+
+// WithPollInterval sets the interval of the polls of the solve, overriding
+// the delay argument of the solver and WithPolling. The interval is rounded
+// down to seconds, shorter intervals are ignored.
+func WithPollInterval(d time.Duration) SolveOption {
+	return func(o *solveOptions) {
+		o.pollInterval = d
+	}
+}
+
+// delay returns the delay between the polls of the solve in seconds,
+// delay if the solve doesn't set its own interval
+func (o *solveOptions) delay(delay time.Duration) time.Duration {
+	if o.pollInterval >= time.Second {
+		return o.pollInterval / time.Second
+	}
+	return delay
+}
+
+// WithInitialWait sets the wait between the submission of the captcha and its
+// first poll, overriding the default of the captcha type, see InitialWait
+func WithInitialWait(d time.Duration) SolveOption {
+	return func(o *solveOptions) {
+		o.initialWait = &d
+	}
+}
+
+// WithExtraParams adds parameters to the in.php form of the solve, e.g. the
+// newly documented parameters not supported by the solver yet. They override
+// the parameters of the solver and of the other options, except key and json
+// which are set by the client. They are validated and logged like the
+// parameters of the solver.
+func WithExtraParams(params map[string]string) SolveOption {
+	return func(o *solveOptions) {
+		o.extra = params
+	}
+}
+
+// solveOptions returns the options of a solve with the defaults of the client
+func (c *TwoCaptchaClient) solveOptions(opts []SolveOption) *solveOptions {
+	o := &solveOptions{timeout: c.maxWait}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// params returns a copy of the API parameters with the options applied
+func (o *solveOptions) params(params map[string]string) map[string]string {
+	p := make(map[string]string, len(params))
+	for k, v := range params {
+		p[k] = v
+	}
+	if u, ok := p["pageurl"]; ok {
+		p["pageurl"] = o.pageURL(u)
+	}
+	if o.softID != "" {
+		p["soft_id"] = o.softID
+	}
+	if o.lang != "" {
+		p["lang"] = o.lang
+	}
+	if o.language > 0 {
+		p["language"] = strconv.Itoa(o.language)
+	}
+	if o.headerACAO {
+		p["header_acao"] = "1"
+	}
+	if o.pingback != nil {
+		p["pingback"] = o.pingback.SignedURL()
+	}
+	if o.userAgent != "" {
+		p["userAgent"] = o.userAgent
+	}
+	if len(o.cookies) > 0 {
+		p["cookies"] = formatCookies(o.cookies)
+	}
+	if o.dataS != "" {
+		p["data-s"] = o.dataS
+	}
+	if len(o.instructions) > 0 {
+		p["imginstructions"] = base64.StdEncoding.EncodeToString(o.instructions)
+	}
+	for k, v := range o.extra {
+		if k != "key" && k != "json" {
+			p[k] = v
+		}
+	}
+	return p
+}
+
+// context returns ctx carrying the per-solve settings used by the API calls.
+// cancel must be called when the solve is done.
+func (o *solveOptions) context(ctx context.Context) (_ context.Context, cancel context.CancelFunc) {
+	cancel = func() {}
+	if o.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+	}
+	if o.format != nil {
+		ctx = context.WithValue(ctx, formatKey{}, *o.format)
+	}
+	if o.pingback != nil {
+		ctx = context.WithValue(ctx, pingbackKey{}, o.pingback)
+	}
+	if o.initialWait != nil {
+		ctx = context.WithValue(ctx, initialWaitKey{}, *o.initialWait)
+	}
+	if o.priority != nil {
+		ctx = context.WithValue(ctx, priorityKey{}, *o.priority)
+	}
+	if o.noQueue {
+		ctx = context.WithValue(ctx, noQueueKey{}, true)
+	}
+	if o.idempotencyKey != "" {
+		ctx = context.WithValue(ctx, idempotencyKey{}, o.idempotencyKey)
+	}
+	ctx = withTags(ctx, o.tags)
+	return ctx, cancel
+}
+
+// timeout returns a *TimeoutError if a solve failed because its own timeout
+// set by WithTimeout or WithMaxWait elapsed, ErrTimeout if the captcha was
+// not submitted yet, err otherwise. The error of the parent context is kept.
+func timeout(parent, ctx context.Context, captchaId string, err error) error {
+	if err == nil || ctx.Err() != context.DeadlineExceeded || parent.Err() != nil {
+		return err
+	}
+	if captchaId == "" {
+		return ErrTimeout
+	}
+	return &TimeoutError{CaptchaID: captchaId}
+}
+
+// pageURL normalizes a page URL
+func (o *solveOptions) pageURL(u string) string {
+	if o.normalization&PageURLAddScheme != 0 && !strings.Contains(u, "://") {
+		u = "https://" + u
+	}
+	if o.normalization&PageURLStripQuery != 0 {
+		if i := strings.IndexAny(u, "?#"); i >= 0 {
+			u = u[:i]
+		}
+	}
+	return u
+}
+
+// formatCookies formats cookies in the key1:value1;key2:value2 format of the API
+func formatCookies(cookies map[string]string) string {
+	pairs := make([]string, 0, len(cookies))
+	for k, v := range cookies {
+		pairs = append(pairs, k+":"+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ";")
+}
@@ -0,0 +1,228 @@
+package twocaptcha
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gocolly/twocaptcha/twocaptchatest"
+)
+
+// testRecaptchaParams are the parameters of the reCAPTCHAs solved in the tests
+var testRecaptchaParams = map[string]string{"googlekey": "SITEKEY", "pageurl": "https://example.com"}
+
+func TestWithResultFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		client ResultFormat
+		solve  []SolveOption
+		want   ResultFormat
+	}{
+		{"client text", TextFormat, nil, TextFormat},
+		{"client JSON", JSONFormat, nil, JSONFormat},
+		{"text overridden", TextFormat, []SolveOption{WithResultFormat(JSONFormat)}, JSONFormat},
+		{"JSON overridden", JSONFormat, []SolveOption{WithResultFormat(TextFormat)}, TextFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := twocaptchatest.NewServer()
+			defer s.Close()
+			s.Price = 0.003
+			c := newTestClient(s)
+			c.ResultFormat = tt.client
+
+			res, err := c.SolveCustom(context.Background(), "userrecaptcha", testRecaptchaParams, append(tt.solve, WithInitialWait(0))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Answer != "TOKEN" {
+				t.Errorf("Answer = %q, want TOKEN", res.Answer)
+			}
+			// the price is only reported in JSONFormat
+			if got := res.Cost > 0; got != (tt.want == JSONFormat) {
+				t.Errorf("Cost = %v in format %v", res.Cost, tt.want)
+			}
+			for _, r := range s.Requests() {
+				if got := r.Get("json") == "1"; got != (tt.want == JSONFormat) {
+					t.Errorf("request %v sent json=%q, want format %v", r, r.Get("json"), tt.want)
+				}
+			}
+		})
+	}
+}
+
+// TestWithResultFormatMixed solves captchas in both formats concurrently
+// with the same client
+func TestWithResultFormatMixed(t *testing.T) {
+	s := twocaptchatest.NewServer()
+	defer s.Close()
+	s.Polls = 1
+	c := newTestClient(s)
+	c.ResultFormat = TextFormat
+
+	text, textErrs := solveParallel(c, parallelism)
+	json, jsonErrs := solveParallel(c, parallelism, WithResultFormat(JSONFormat))
+	for i := 0; i < parallelism; i++ {
+		if textErrs[i] != nil || jsonErrs[i] != nil {
+			t.Fatalf("solve %d: %v, %v", i, textErrs[i], jsonErrs[i])
+		}
+		if text[i].Answer != "TOKEN" || json[i].Answer != "TOKEN" {
+			t.Errorf("solve %d: answers %q and %q, want TOKEN", i, text[i].Answer, json[i].Answer)
+		}
+	}
+
+	actions := make(map[string]int)
+	for _, r := range s.Requests() {
+		if action := r.Get("action"); action != "" {
+			if (action == "get2") != (r.Get("json") == "1") {
+				t.Errorf("poll %s sent json=%q", action, r.Get("json"))
+			}
+			actions[action]++
+		}
+	}
+	// every captcha is polled twice, the first poll is not ready
+	if actions["get"] != 2*parallelism || actions["get2"] != 2*parallelism {
+		t.Errorf("polls = %v, want %d of get and get2", actions, 2*parallelism)
+	}
+}
+
+// TestWithResultFormatErrors checks the errors are parsed in the format of
+// the solve
+func TestWithResultFormatErrors(t *testing.T) {
+	s := twocaptchatest.NewServer()
+	defer s.Close()
+	c := newTestClient(s)
+
+	for _, f := range []ResultFormat{TextFormat, JSONFormat} {
+		s.Update(func(s *twocaptchatest.Server) { s.SubmitError = CodeZeroBalance })
+		_, err := c.SolveCustom(context.Background(), "userrecaptcha", testRecaptchaParams, WithResultFormat(f))
+		if !errors.Is(err, ErrZeroBalance) {
+			t.Errorf("format %v: submit error = %v, want ErrZeroBalance", f, err)
+		}
+
+		s.Update(func(s *twocaptchatest.Server) {
+			s.SubmitError = ""
+			s.ResultError = CodeCaptchaUnsolvable
+		})
+		_, err = c.SolveCustom(context.Background(), "userrecaptcha", testRecaptchaParams, WithResultFormat(f), WithInitialWait(0))
+		if !errors.Is(err, ErrCaptchaUnsolvable) {
+			t.Errorf("format %v: result error = %v, want ErrCaptchaUnsolvable", f, err)
+		}
+		s.Update(func(s *twocaptchatest.Server) { s.ResultError = "" })
+	}
+}
+
+func TestWithDefaultLang(t *testing.T) {
+	s := twocaptchatest.NewServer()
+	defer s.Close()
+	c := newTestClient(s, WithDefaultLang("en"))
+
+	for _, tt := range []struct {
+		opts []SolveOption
+		want string
+	}{
+		{nil, "en"},
+		{[]SolveOption{WithLang("pt")}, "pt"},
+	} {
+		if _, err := c.SolveCustom(context.Background(), "userrecaptcha", testRecaptchaParams, append(tt.opts, WithInitialWait(0))...); err != nil {
+			t.Fatal(err)
+		}
+		var lang string
+		for _, r := range s.Requests() {
+			if r.Get("method") == "userrecaptcha" {
+				lang = r.Get("lang")
+			}
+		}
+		if lang != tt.want {
+			t.Errorf("lang = %q, want %q", lang, tt.want)
+		}
+	}
+}
+
+// TestSolveDelay solves captchas taking a while to be solved by the server
+func TestSolveDelay(t *testing.T) {
+	s := twocaptchatest.NewServer()
+	defer s.Close()
+	s.Delay = 200 * time.Millisecond
+	c := newTestClient(s)
+	c.Backoff = ConstantBackoff(20 * time.Millisecond)
+
+	res, err := c.SolveCustom(context.Background(), "userrecaptcha", testRecaptchaParams, WithInitialWait(0), WithTimeout(50*time.Millisecond))
+	var timeout *TimeoutError
+	if !errors.As(err, &timeout) || timeout.CaptchaID == "" {
+		t.Fatalf("error = %v, want a TimeoutError with the captcha ID", err)
+	}
+	if res.Answer != "" {
+		t.Errorf("Answer = %q after the timeout", res.Answer)
+	}
+
+	res, err = c.SolveCustom(context.Background(), "userrecaptcha", testRecaptchaParams, WithInitialWait(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Answer != "TOKEN" {
+		t.Errorf("Answer = %q, want TOKEN", res.Answer)
+	}
+}
+
+// TestWithIdempotencyKeyTimeout checks a timed out captcha blocks its key
+// until it is recovered
+func TestWithIdempotencyKeyTimeout(t *testing.T) {
+	s := twocaptchatest.NewServer()
+	defer s.Close()
+	s.Delay = time.Hour
+	store, err := NewFileTaskStore(filepath.Join(t.TempDir(), "tasks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newTestClient(s, WithTaskStore(store))
+
+	opts := []SolveOption{WithInitialWait(0), WithIdempotencyKey("job"), WithTimeout(50 * time.Millisecond)}
+	res, err := c.SolveCustom(context.Background(), "userrecaptcha", testRecaptchaParams, opts...)
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("error = %v, want ErrTimeout", err)
+	}
+	retry, err := c.SolveCustom(context.Background(), "userrecaptcha", testRecaptchaParams, opts...)
+	if !errors.Is(err, ErrDuplicateTask) || retry.ID != res.ID {
+		t.Errorf("retry = %q, %v, want %q and ErrDuplicateTask", retry.ID, err, res.ID)
+	}
+}
+
+// TestWithImageCacheLang checks the answers are cached per language
+func TestWithImageCacheLang(t *testing.T) {
+	s := twocaptchatest.NewServer()
+	defer s.Close()
+	c := newTestClient(s, WithImageCache(NewMemoryCache(), time.Hour))
+
+	img := image.NewGray(image.Rect(0, 0, 120, 40))
+	for x := 0; x < 120; x++ {
+		img.SetGray(x, 20, color.Gray{Y: 255})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, tt := range []struct {
+		lang   string
+		cached bool
+	}{
+		{"en", false},
+		{"en", true},
+		{"pt", false},
+	} {
+		res, err := c.SolveImageCaptcha(context.Background(), buf.Bytes(), ImageOptions{}, WithLang(tt.lang), WithInitialWait(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Cached != tt.cached {
+			t.Errorf("solve %d in %s: Cached = %v, want %v", i, tt.lang, res.Cached, tt.cached)
+		}
+	}
+}
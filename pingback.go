@@ -0,0 +1,237 @@
+package twocaptcha
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// pingbackResultTTL is the time the answers nobody waits for are kept,
+	// e.g. of the solves which timed out before the answer arrived
+	pingbackResultTTL = 10 * time.Minute
+	// maxPingbackResults is the number of the answers nobody waits for kept
+	// at most, the oldest ones are dropped first
+	maxPingbackResults = 10000
+)
+
+// ErrPingbackRejected is returned by VerifyPingback if a request did not come
+// from 2captcha
+var ErrPingbackRejected = errors.New("Pingback request rejected")
+
+// PingbackServer is a http.Handler receiving the answers of the captchas
+// solved with WithPingback. 2captcha sends the answer to the server as soon
+// as the captcha is solved, the solves waiting for it don't poll res.php.
+// The zero value with URL set is ready to use.
+// See more details on https://2captcha.com/2captcha-api#pingback
+type PingbackServer struct {
+	// URL is the public URL of the server passed to 2captcha as pingback.
+	// It must be registered in the account settings on 2captcha.com.
+	URL string
+	// Secret signs the pingback URL passed to 2captcha: a token query
+	// parameter derived from Secret with HMAC-SHA256 is added to URL and
+	// the requests without the same token are rejected. Secret itself is
+	// never sent.
+	Secret string
+	// AllowedIPs are the IP addresses and CIDR ranges, e.g. "10.0.0.0/8",
+	// the answers are accepted from. The addresses are matched with the
+	// remote address of the request, the answers are accepted from any
+	// address if it is empty.
+	AllowedIPs []string
+
+	mu      sync.Mutex
+	waiting map[string]chan *response
+	// results holds the answers arrived before their solve started waiting
+	results map[string]pingbackResult
+}
+
+// pingbackResult is an answer nobody waited for when it arrived
+type pingbackResult struct {
+	res     *response
+	arrived time.Time
+}
+
+// pingbackKey is the context key of the PingbackServer of a single solve
+type pingbackKey struct{}
+
+// NewPingbackServer creates a PingbackServer reachable on URL
+func NewPingbackServer(URL string) *PingbackServer {
+	return &PingbackServer{URL: URL}
+}
+
+// WithPingback makes the solve wait for its answer on s instead of polling res.php
+func WithPingback(s *PingbackServer) SolveOption {
+	return func(o *solveOptions) {
+		o.pingback = s
+	}
+}
+
+// ServeHTTP receives an answer sent by 2captcha and passes it to the waiting solve
+func (s *PingbackServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := s.VerifyPingback(r); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	id := r.FormValue("id")
+	code := r.FormValue("code")
+	if !captchaIdPattern.MatchString(id) {
+		http.Error(w, "Invalid captcha ID", http.StatusBadRequest)
+		return
+	}
+	res := &response{OK: !strings.HasPrefix(code, "ERROR_"), Answer: code}
+
+	s.mu.Lock()
+	if ch, ok := s.waiting[id]; ok {
+		delete(s.waiting, id)
+		ch <- res
+	} else {
+		s.keep(id, res)
+	}
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+// VerifyPingback checks that r was sent by 2captcha: it carries the token of
+// Secret and comes from one of AllowedIPs. ErrPingbackRejected is returned
+// otherwise. It is called by ServeHTTP, use it if the answers are received
+// by another handler.
+func (s *PingbackServer) VerifyPingback(r *http.Request) error {
+	if s.Secret != "" && !hmac.Equal([]byte(r.FormValue("token")), []byte(s.token())) {
+		return ErrPingbackRejected
+	}
+	if len(s.AllowedIPs) == 0 {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return ErrPingbackRejected
+	}
+	for _, allowed := range s.AllowedIPs {
+		if _, network, err := net.ParseCIDR(allowed); err == nil {
+			if network.Contains(ip) {
+				return nil
+			}
+		} else if allowedIP := net.ParseIP(allowed); allowedIP != nil && allowedIP.Equal(ip) {
+			return nil
+		}
+	}
+	return ErrPingbackRejected
+}
+
+// SignedURL returns the URL passed to 2captcha as pingback, URL with the
+// token of Secret if it is set
+func (s *PingbackServer) SignedURL() string {
+	if s.Secret == "" {
+		return s.URL
+	}
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return s.URL
+	}
+	q := u.Query()
+	q.Set("token", s.token())
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// token returns the HMAC-SHA256 of URL keyed with Secret
+func (s *PingbackServer) token() string {
+	mac := hmac.New(sha256.New, []byte(s.Secret))
+	mac.Write([]byte(s.URL))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Wait waits for the answer of a captcha submitted with the SignedURL of s
+// as pingback
+func (s *PingbackServer) Wait(ctx context.Context, captchaId string) (CaptchaResult, error) {
+	res, err := s.wait(ctx, captchaId, 0)
+	if err != nil {
+		return CaptchaResult{ID: captchaId}, err
+	}
+	return CaptchaResult{ID: captchaId, Answer: res.Answer, SolvedAt: time.Now()}, nil
+}
+
+// wait waits for the answer of a captcha until ctx is done or timeout
+// elapses, ErrTimeout is returned in the latter case. A zero timeout waits
+// until ctx is done.
+func (s *PingbackServer) wait(ctx context.Context, captchaId string, timeout time.Duration) (*response, error) {
+	s.mu.Lock()
+	if r, ok := s.results[captchaId]; ok {
+		delete(s.results, captchaId)
+		s.mu.Unlock()
+		return checkPingback(r.res)
+	}
+	ch := make(chan *response, 1)
+	if s.waiting == nil {
+		s.waiting = make(map[string]chan *response)
+	}
+	s.waiting[captchaId] = ch
+	s.mu.Unlock()
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		expired = t.C
+	}
+	select {
+	case res := <-ch:
+		return checkPingback(res)
+	case <-ctx.Done():
+		s.cancel(captchaId, ch)
+		return nil, ctx.Err()
+	case <-expired:
+		s.cancel(captchaId, ch)
+		return nil, &TimeoutError{CaptchaID: captchaId}
+	}
+}
+
+// keep keeps an answer nobody waits for yet and drops the expired ones.
+// s.mu must be held.
+func (s *PingbackServer) keep(captchaId string, res *response) {
+	if s.results == nil {
+		s.results = make(map[string]pingbackResult)
+	}
+	now := time.Now()
+	oldest := ""
+	for id, r := range s.results {
+		if now.Sub(r.arrived) >= pingbackResultTTL {
+			delete(s.results, id)
+		} else if oldest == "" || r.arrived.Before(s.results[oldest].arrived) {
+			oldest = id
+		}
+	}
+	if len(s.results) >= maxPingbackResults {
+		delete(s.results, oldest)
+	}
+	s.results[captchaId] = pingbackResult{res: res, arrived: now}
+}
+
+// cancel stops waiting for the answer of a captcha
+func (s *PingbackServer) cancel(captchaId string, ch chan *response) {
+	s.mu.Lock()
+	if s.waiting[captchaId] == ch {
+		delete(s.waiting, captchaId)
+	}
+	s.mu.Unlock()
+}
+
+// checkPingback returns the APIError of a failed captcha
+func checkPingback(res *response) (*response, error) {
+	if !res.OK {
+		return nil, &APIError{Code: res.Answer}
+	}
+	return res, nil
+}
@@ -0,0 +1,63 @@
+package twocaptcha
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// DefaultMinPollInterval is the minimum interval between the polls of the
+// same captcha ID of the clients created by New. 2captcha may ban the IP
+// addresses polling a captcha more often.
+const DefaultMinPollInterval = 5 * time.Second
+
+// WithMinPollInterval sets the minimum interval between the polls of the same
+// captcha ID, DefaultMinPollInterval by default. The polls are delayed to
+// keep the interval regardless of the delay, the Backoff and the calls of
+// Result. Zero disables the limit, e.g. for a mock API in tests.
+func WithMinPollInterval(d time.Duration) Option {
+	return func(c *TwoCaptchaClient) {
+		c.minPoll = d
+	}
+}
+
+// pollGate waits until captchaId can be polled again and reserves the poll
+func (c *TwoCaptchaClient) pollGate(ctx context.Context, captchaId string) error {
+	if c.minPoll <= 0 || captchaId == "" {
+		return nil
+	}
+	c.mu.Lock()
+	now := time.Now()
+	next := now
+	if last, ok := c.polls[captchaId]; ok && last.Add(c.minPoll).After(now) {
+		next = last.Add(c.minPoll)
+	}
+	if c.polls == nil {
+		c.polls = make(map[string]time.Time)
+	}
+	for id, last := range c.polls {
+		// the abandoned polls do not limit anything anymore
+		if now.Sub(last) >= c.minPoll {
+			delete(c.polls, id)
+		}
+	}
+	c.polls[captchaId] = next
+	c.mu.Unlock()
+	return sleep(ctx, next.Sub(now))
+}
+
+// polled forgets captchaId after its answer was fetched, the failed polls
+// may be retried and are forgotten once the interval elapsed
+func (c *TwoCaptchaClient) polled(captchaId string, err error) {
+	if c.minPoll <= 0 || err != nil {
+		return
+	}
+	c.mu.Lock()
+	delete(c.polls, captchaId)
+	c.mu.Unlock()
+}
+
+// isPoll reports whether params fetch the answer of a captcha from res.php
+func isPoll(params map[string]string) bool {
+	return params["id"] != "" && strings.HasPrefix(params["action"], "get")
+}
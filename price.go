@@ -0,0 +1,106 @@
+package twocaptcha
+
+import (
+	"errors"
+	"time"
+)
+
+// CaptchaType identifies a captcha type supported by 2captcha
+type CaptchaType string
+
+// Captcha types supported by the client
+const (
+	ImageType       CaptchaType = "image"
+	RecaptchaV2Type CaptchaType = "recaptcha_v2"
+	RecaptchaV3Type CaptchaType = "recaptcha_v3"
+	HCaptchaType    CaptchaType = "hcaptcha"
+	GridType        CaptchaType = "grid"
+	CutcaptchaType  CaptchaType = "cutcaptcha"
+	GeeTestType     CaptchaType = "geetest"
+	GeeTestV4Type   CaptchaType = "geetest_v4"
+	FunCaptchaType  CaptchaType = "funcaptcha"
+	TurnstileType   CaptchaType = "turnstile"
+	TextType        CaptchaType = "text"
+	KeyCaptchaType  CaptchaType = "keycaptcha"
+	CapyType        CaptchaType = "capy"
+	LeminType       CaptchaType = "lemin"
+	AmazonWAFType   CaptchaType = "amazon_waf"
+	CoordinatesType CaptchaType = "coordinates"
+	RotateType      CaptchaType = "rotate"
+	AudioType       CaptchaType = "audio"
+	CanvasType      CaptchaType = "canvas"
+	DataDomeType    CaptchaType = "datadome"
+	MTCaptchaType   CaptchaType = "mtcaptcha"
+	FriendlyType    CaptchaType = "friendly_captcha"
+	CyberSiARAType  CaptchaType = "cybersiara"
+	TencentType     CaptchaType = "tencent"
+	BoundingBoxType CaptchaType = "bounding_box"
+	SliderType      CaptchaType = "slider"
+	YandexType      CaptchaType = "yandex"
+)
+
+// MethodInfo describes a captcha type supported by the client
+type MethodInfo struct {
+	// Type is the captcha type
+	Type CaptchaType
+	// Method is the value of the method parameter sent to in.php
+	Method string
+	// Price is the maximum price of a captcha in USD
+	Price float64
+	// ETA is the average solving time of a captcha
+	ETA time.Duration
+}
+
+// methods contains the captcha types supported by the client with their
+// prices and average solving times as published on https://2captcha.com/pricing
+var methods = []MethodInfo{
+	{ImageType, "base64", 0.001, 12 * time.Second},
+	{RecaptchaV2Type, "userrecaptcha", 0.00299, 45 * time.Second},
+	{RecaptchaV3Type, "userrecaptcha", 0.00299, 15 * time.Second},
+	{HCaptchaType, "hcaptcha", 0.00299, 30 * time.Second},
+	{GridType, "base64", 0.001, 20 * time.Second},
+	{CutcaptchaType, "cutcaptcha", 0.00299, 30 * time.Second},
+	{GeeTestType, "geetest", 0.00299, 30 * time.Second},
+	{GeeTestV4Type, "geetest_v4", 0.00299, 30 * time.Second},
+	{FunCaptchaType, "funcaptcha", 0.00299, 30 * time.Second},
+	{TurnstileType, "turnstile", 0.00145, 15 * time.Second},
+	{TextType, "textcaptcha", 0.001, 15 * time.Second},
+	{KeyCaptchaType, "keycaptcha", 0.00299, 30 * time.Second},
+	{CapyType, "capy", 0.00299, 30 * time.Second},
+	{LeminType, "lemin", 0.00299, 30 * time.Second},
+	{AmazonWAFType, "amazon_waf", 0.00145, 20 * time.Second},
+	{CoordinatesType, "base64", 0.001, 20 * time.Second},
+	{RotateType, "rotatecaptcha", 0.001, 20 * time.Second},
+	{AudioType, "audio", 0.001, 20 * time.Second},
+	{CanvasType, "base64", 0.001, 20 * time.Second},
+	{DataDomeType, "datadome", 0.00299, 30 * time.Second},
+	{MTCaptchaType, "mt_captcha", 0.00299, 30 * time.Second},
+	{FriendlyType, "friendly_captcha", 0.00299, 30 * time.Second},
+	{CyberSiARAType, "cybersiara", 0.00299, 30 * time.Second},
+	{TencentType, "tencent", 0.00299, 30 * time.Second},
+	{BoundingBoxType, "base64", 0.001, 20 * time.Second},
+	{SliderType, "post", 0.001, 20 * time.Second},
+	{YandexType, "yandex", 0.00299, 30 * time.Second},
+}
+
+// GetPriceAndETA returns the price of a captcha in USD and its estimated solving time.
+// 2captcha does not provide an API for the current rates, so the values are
+// the documented list prices and average solving times. The actual price
+// depends on the load of the service and may be lower.
+func (c *TwoCaptchaClient) GetPriceAndETA(t CaptchaType) (float64, time.Duration, error) {
+	for _, m := range methods {
+		if m.Type == t {
+			return m.Price, m.ETA, nil
+		}
+	}
+	return 0, 0, errors.New("Unknown captcha type: " + string(t))
+}
+
+// SupportedMethods returns the captcha types supported by the client.
+// 2captcha does not provide an API listing its methods, so the list is
+// static and contains the documented list prices, see GetPriceAndETA.
+func (c *TwoCaptchaClient) SupportedMethods() ([]MethodInfo, error) {
+	res := make([]MethodInfo, len(methods))
+	copy(res, methods)
+	return res, nil
+}
@@ -0,0 +1,128 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrTooManyInFlight is returned by the solves with WithoutQueueing if all
+// the solving slots of the client are taken
+var ErrTooManyInFlight = errors.New("Too many captchas in flight")
+
+// Priority is the priority of a solve waiting for a solving slot of a client
+// created with WithMaxConcurrent
+type Priority int
+
+// Priorities of the solves, Normal is the default
+const (
+	Low Priority = iota - 1
+	Normal
+	High
+)
+
+// priorityKey is the context key of the priority of a single solve
+type priorityKey struct{}
+
+// WithPriority sets the priority of the solve. When all the solving slots of
+// a client created with WithMaxConcurrent are taken, the freed slots are
+// given to the waiting solves of the highest priority first, e.g. to let
+// the user-facing solves preempt the background solves. The solves of the
+// same priority get the slots in the order they started waiting.
+func WithPriority(p Priority) SolveOption {
+	return func(o *solveOptions) {
+		o.priority = &p
+	}
+}
+
+// noQueueKey is the context key of WithoutQueueing
+type noQueueKey struct{}
+
+// WithoutQueueing makes the solve fail with ErrTooManyInFlight instead of
+// waiting if all the solving slots of a client created with
+// WithMaxConcurrent are taken, e.g. to fail fast in user-facing flows
+func WithoutQueueing() SolveOption {
+	return func(o *solveOptions) {
+		o.noQueue = true
+	}
+}
+
+// priority returns the priority of the solve of ctx
+func priority(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return p
+	}
+	return Normal
+}
+
+// slots are the solving slots of a client, the waiting solves are queued by
+// their priority
+type slots struct {
+	mu      sync.Mutex
+	free    int
+	waiting map[Priority][]chan struct{}
+}
+
+func newSlots(n int) *slots {
+	return &slots{free: n, waiting: make(map[Priority][]chan struct{})}
+}
+
+// acquire takes a slot, waiting for it until ctx is done unless the solve
+// is not queued
+func (s *slots) acquire(ctx context.Context, p Priority) error {
+	s.mu.Lock()
+	if s.free > 0 {
+		s.free--
+		s.mu.Unlock()
+		return nil
+	}
+	if ctx.Value(noQueueKey{}) != nil {
+		s.mu.Unlock()
+		return ErrTooManyInFlight
+	}
+	ch := make(chan struct{})
+	s.waiting[p] = append(s.waiting[p], ch)
+	s.mu.Unlock()
+
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, waiting := range s.waiting[p] {
+			if waiting == ch {
+				s.waiting[p] = append(s.waiting[p][:i], s.waiting[p][i+1:]...)
+				return ctx.Err()
+			}
+		}
+		// the slot was given to the solve after ctx was done, pass it on
+		s.handOff()
+		return ctx.Err()
+	}
+}
+
+// release frees a slot taken by acquire
+func (s *slots) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handOff()
+}
+
+// handOff gives a freed slot to the oldest solve of the highest priority
+// waiting for one, s.mu must be held
+func (s *slots) handOff() {
+	best, found := Normal, false
+	for p, waiting := range s.waiting {
+		if len(waiting) > 0 && (!found || p > best) {
+			best, found = p, true
+		}
+	}
+	if !found {
+		s.free++
+		return
+	}
+	waiting := s.waiting[best]
+	s.waiting[best] = waiting[1:]
+	close(waiting[0])
+}
@@ -0,0 +1,58 @@
+package twocaptcha
+
+// ClientProfile contains the integration defaults applied to every captcha
+// submitted by a client.
+//
+// The values are used in the following order of precedence:
+// the SolveOption of the solve (e.g. WithSoftID, WithLang, WithProxy),
+// then the fields of the client (SoftID, Proxy), then the profile.
+// Empty profile fields are not sent.
+type ClientProfile struct {
+	// SoftID is the ID of the software registered in the 2captcha developer program
+	SoftID string
+	// HeaderACAO makes the API send the Access-Control-Allow-Origin: * header
+	HeaderACAO bool
+	// DefaultLang is the default language of the workers, e.g. "en",
+	// see also WithDefaultLang
+	DefaultLang string
+	// DefaultProxy is the default proxy of the captcha types supporting proxies.
+	// It is set as the Proxy of the client.
+	DefaultProxy *Proxy
+}
+
+// WithProfile sets the integration defaults of the client
+func WithProfile(p ClientProfile) Option {
+	return func(c *TwoCaptchaClient) {
+		c.profile = p
+		if p.DefaultProxy != nil {
+			c.Proxy = p.DefaultProxy
+		}
+	}
+}
+
+// clientProfile returns the profile of the client with its SoftID
+func (c *TwoCaptchaClient) clientProfile() ClientProfile {
+	p := c.profile
+	if c.SoftID != "" {
+		p.SoftID = c.SoftID
+	}
+	return p
+}
+
+// params returns a copy of the submit parameters with the missing defaults added
+func (p ClientProfile) params(params map[string]string) map[string]string {
+	res := make(map[string]string, len(params)+3)
+	for k, v := range params {
+		res[k] = v
+	}
+	if _, ok := res["soft_id"]; !ok && p.SoftID != "" {
+		res["soft_id"] = p.SoftID
+	}
+	if _, ok := res["lang"]; !ok && p.DefaultLang != "" {
+		res["lang"] = p.DefaultLang
+	}
+	if p.HeaderACAO {
+		res["header_acao"] = "1"
+	}
+	return res
+}
@@ -0,0 +1,49 @@
+package twocaptcha
+
+import "errors"
+
+// errNoTaskAPI is returned by the JSON API v2 requests of a Provider without TaskURL
+var errNoTaskAPI = errors.New("JSON API v2 is not supported by the provider")
+
+// Provider is a captcha solving service compatible with the 2captcha API
+type Provider struct {
+	// Name is the name of the service
+	Name string
+	// BaseURL is the URL of in.php and res.php of the service
+	BaseURL string
+	// TaskURL is the URL of the JSON API v2 of the service, empty if the
+	// service doesn't support it. SolveTask solves the tasks supported by
+	// the legacy API with in.php and res.php in that case.
+	TaskURL string
+	// OKPrefix is the prefix of the successful responses in TextFormat,
+	// "OK|" if it is empty
+	OKPrefix string
+}
+
+// Providers compatible with the 2captcha API
+var (
+	// TwoCaptcha is https://2captcha.com, used by default
+	TwoCaptcha = Provider{
+		Name:     "2captcha",
+		BaseURL:  "https://2captcha.com",
+		TaskURL:  "https://api.2captcha.com",
+		OKPrefix: "OK|",
+	}
+	// RuCaptcha is https://rucaptcha.com
+	RuCaptcha = Provider{
+		Name:     "rucaptcha",
+		BaseURL:  "https://rucaptcha.com",
+		TaskURL:  "https://api.rucaptcha.com",
+		OKPrefix: "OK|",
+	}
+)
+
+// WithProvider sends the requests of the client to p instead of 2captcha
+func WithProvider(p Provider) Option {
+	return func(c *TwoCaptchaClient) {
+		WithBaseURL(p.BaseURL)(c)
+		WithTaskURL(p.TaskURL)(c)
+		c.okPrefix = p.OKPrefix
+		c.noTaskAPI = p.TaskURL == ""
+	}
+}
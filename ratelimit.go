@@ -0,0 +1,79 @@
+package twocaptcha
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket limiting the requests per second to an endpoint
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter creates a rateLimiter allowing rps requests per second
+// with bursts of up to rps requests, at least 1
+func newRateLimiter(rps float64) *rateLimiter {
+	burst := float64(int(rps))
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{rate: rps, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// wait takes a token from the bucket, waiting for it if the bucket is empty
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+	var d time.Duration
+	if l.tokens < 0 {
+		d = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if err := sleep(ctx, d); err != nil {
+		// return the unused token
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// WithRateLimit limits the requests of the client per second to in.php to
+// submitRPS and to res.php to resultRPS. The limits are shared by all the
+// goroutines using the client, a zero limit disables the limiting of the
+// endpoint. 2captcha throttles and bans the clients polling too fast.
+func WithRateLimit(submitRPS, resultRPS float64) Option {
+	return func(c *TwoCaptchaClient) {
+		if submitRPS > 0 {
+			c.submitLimiter = newRateLimiter(submitRPS)
+		}
+		if resultRPS > 0 {
+			c.resultLimiter = newRateLimiter(resultRPS)
+		}
+	}
+}
+
+// limit waits until a request to URL is allowed by the rate limits of the client
+func (c *TwoCaptchaClient) limit(ctx context.Context, URL string) error {
+	l := c.submitLimiter
+	if URL == c.resultURL() {
+		l = c.resultLimiter
+	}
+	if l == nil {
+		return nil
+	}
+	return l.wait(ctx)
+}
@@ -0,0 +1,246 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// RecaptchaOptions contains the optional parameters of a reCAPTCHA solving request.
+// The API solves the web widgets only. The site keys of the reCAPTCHA SDKs of
+// Android and iOS apps are not supported, their tokens are bound to the
+// attestation of the app and are rejected when solved as web site keys.
+type RecaptchaOptions struct {
+	// Version is the version of the captcha, "v2" or "v3". Defaults to "v2".
+	Version string
+	// Enterprise marks the captcha as reCAPTCHA Enterprise
+	Enterprise bool
+	// EnterprisePayload is the additional payload required by some
+	// reCAPTCHA Enterprise widgets. It is sent as data[key]=value fields.
+	EnterprisePayload map[string]string
+	// Invisible marks a reCAPTCHA v2 captcha as invisible
+	Invisible bool
+	// DataS is the data-s value of the captchas on Google services, e.g.
+	// Google Search. Their tokens are rejected without it, see WithDataS.
+	DataS string
+	// Domain is the domain the widget is loaded from, "google.com" or
+	// "recaptcha.net". Defaults to google.com.
+	Domain string
+
+	// Action is the action of a reCAPTCHA v3 captcha, it is omitted if empty
+	Action string
+	// MinScore is the minimum score of a reCAPTCHA v3 token, sent to the API
+	// if it is set, e.g. 0.3 or 0.35
+	MinScore float64
+	// MaxScore is the maximum score of a reCAPTCHA v3 token. It is not
+	// supported by the API and checked by the client using Score.
+	MaxScore float64
+	// Score returns the score of a reCAPTCHA v3 token, e.g. using the
+	// siteverify API of a site you own
+	Score func(token string) (float64, error)
+	// MaxAttempts is the number of reCAPTCHA v3 solves until a token with
+	// a score within the range is found, 3 by default
+	MaxAttempts int
+}
+
+// SolveRecaptchaV2 performs a recaptcha v2 solving request to 2captcha.com
+// and returns with the solved captcha and captcha ID if the request was successful.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#solving_recaptchav2_new
+//
+// Deprecated: use the Solve of the solver package with a RecaptchaV2Task.
+func (c *TwoCaptchaClient) SolveRecaptchaV2(siteURL, recaptchaKey string, delay time.Duration, retries int, opts ...SolveOption) (string, string, error) {
+	return c.SolveRecaptchaV2WithContext(context.Background(), siteURL, recaptchaKey, delay, retries, opts...)
+}
+
+// SolveRecaptchaV2WithContext is SolveRecaptchaV2 with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a RecaptchaV2Task.
+func (c *TwoCaptchaClient) SolveRecaptchaV2WithContext(ctx context.Context, siteURL, recaptchaKey string, delay time.Duration, retries int, opts ...SolveOption) (string, string, error) {
+	return c.SolveRecaptchaWithContext(ctx, siteURL, recaptchaKey, RecaptchaOptions{Version: "v2"}, delay, retries, opts...)
+}
+
+// SolveRecaptchaV3 performs a recaptcha v3 solving request to 2captcha.com
+// and returns with the solved captcha if the request was successful.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/solving_recaptcha_v3
+//
+// Deprecated: use the Solve of the solver package with a RecaptchaV3Task.
+func (c *TwoCaptchaClient) SolveRecaptchaV3(siteURL, recaptchaKey, action string, minScore float64, opts ...SolveOption) (string, error) {
+	return c.SolveRecaptchaV3WithContext(context.Background(), siteURL, recaptchaKey, action, minScore, opts...)
+}
+
+// SolveRecaptchaV3WithContext is SolveRecaptchaV3 with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a RecaptchaV3Task.
+func (c *TwoCaptchaClient) SolveRecaptchaV3WithContext(ctx context.Context, siteURL, recaptchaKey, action string, minScore float64, opts ...SolveOption) (string, error) {
+	delay, retries := c.polling()
+	token, _, err := c.SolveRecaptchaWithContext(
+		ctx,
+		siteURL,
+		recaptchaKey,
+		RecaptchaOptions{Version: "v3", Action: action, MinScore: minScore},
+		delay,
+		retries,
+		opts...,
+	)
+	return token, err
+}
+
+// SolveRecaptchaV3WithOptions performs a recaptcha v3 solving request to 2captcha.com
+// using the Action, MinScore, MaxScore, Score and Enterprise options and returns with
+// the solved captcha if the request was successful. See SolveRecaptcha for the details.
+func (c *TwoCaptchaClient) SolveRecaptchaV3WithOptions(siteURL, recaptchaKey string, opts RecaptchaOptions, solveOpts ...SolveOption) (string, error) {
+	opts.Version = "v3"
+	delay, retries := c.polling()
+	token, _, err := c.SolveRecaptchaWithContext(context.Background(), siteURL, recaptchaKey, opts, delay, retries, solveOpts...)
+	return token, err
+}
+
+// SolveRecaptcha performs a recaptcha solving request of any version and
+// flavour to 2captcha.com and returns with the solved captcha and captcha ID
+// if the request was successful.
+//
+// For reCAPTCHA v3 only MinScore is supported by the API. If Score is set, the
+// score of every token is checked by the client, tokens below MinScore are
+// reported as bad and tokens outside of the MinScore-MaxScore range are
+// resubmitted up to MaxAttempts times. ErrScoreOutOfRange is returned if no
+// token was found within the range.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#solving_recaptchav2_new
+// and https://2captcha.com/2captcha-api#solving_recaptchav3
+//
+// Deprecated: use the Solve of the solver package with a RecaptchaV2Task or
+// RecaptchaV3Task, or SolveRecaptchaV3WithOptions for the Score options.
+func (c *TwoCaptchaClient) SolveRecaptcha(siteURL, recaptchaKey string, opts RecaptchaOptions, delay time.Duration, retries int, solveOpts ...SolveOption) (string, string, error) {
+	return c.SolveRecaptchaWithContext(context.Background(), siteURL, recaptchaKey, opts, delay, retries, solveOpts...)
+}
+
+// SolveRecaptchaWithContext is SolveRecaptcha with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a RecaptchaV2Task or
+// RecaptchaV3Task, or SolveRecaptchaV3WithOptions for the Score options.
+func (c *TwoCaptchaClient) SolveRecaptchaWithContext(ctx context.Context, siteURL, recaptchaKey string, opts RecaptchaOptions, delay time.Duration, retries int, solveOpts ...SolveOption) (string, string, error) {
+	res, err := c.solveRecaptcha(ctx, siteURL, recaptchaKey, opts, delay, retries, solveOpts)
+	return res.Answer, res.ID, err
+}
+
+// SolveRecaptchaV3Result performs a recaptcha v3 solving request to 2captcha.com
+// and returns with the solved captcha including its ID and metadata, e.g.
+// to report a token rejected by the site with ReportResult.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#solving_recaptchav3
+func (c *TwoCaptchaClient) SolveRecaptchaV3Result(ctx context.Context, siteURL, recaptchaKey, action string, minScore float64, opts ...SolveOption) (CaptchaResult, error) {
+	delay, retries := c.polling()
+	return c.solveRecaptcha(
+		ctx,
+		siteURL,
+		recaptchaKey,
+		RecaptchaOptions{Version: "v3", Action: action, MinScore: minScore},
+		delay,
+		retries,
+		opts,
+	)
+}
+
+// solveRecaptcha solves a reCAPTCHA, see SolveRecaptcha
+func (c *TwoCaptchaClient) solveRecaptcha(ctx context.Context, siteURL, recaptchaKey string, opts RecaptchaOptions, delay time.Duration, retries int, solveOpts []SolveOption) (CaptchaResult, error) {
+	params, err := recaptchaParams(siteURL, recaptchaKey, opts)
+	if err != nil {
+		return CaptchaResult{}, err
+	}
+	if opts.Score == nil {
+		return c.solve(ctx, params, delay, retries, solveOpts)
+	}
+
+	attempts := opts.MaxAttempts
+	if attempts <= 0 {
+		attempts = 3
+	}
+	for i := 0; i < attempts; i++ {
+		res, err := c.solve(ctx, params, delay, retries, solveOpts)
+		if err != nil {
+			return res, err
+		}
+		score, err := opts.Score(res.Answer)
+		if err != nil {
+			return CaptchaResult{ID: res.ID}, err
+		}
+		if score < opts.MinScore {
+			c.ReportResult(ctx, res, false)
+			continue
+		}
+		if opts.MaxScore > 0 && score > opts.MaxScore {
+			continue
+		}
+		return res, nil
+	}
+	return CaptchaResult{}, ErrScoreOutOfRange
+}
+
+// recaptchaParams builds the API parameters of a reCAPTCHA solving request
+// from a RecaptchaV2Task or RecaptchaV3Task, the options not supported by
+// the tasks are added to their parameters
+func recaptchaParams(siteURL, recaptchaKey string, opts RecaptchaOptions) (map[string]string, error) {
+	switch opts.Domain {
+	case "", "google.com", "recaptcha.net":
+	default:
+		return nil, errors.New("Unknown reCAPTCHA domain: " + opts.Domain)
+	}
+	var task legacyTask
+	switch opts.Version {
+	case "", "v2":
+		if opts.Score != nil || opts.MaxScore > 0 {
+			return nil, errors.New("Score options are only supported by reCAPTCHA v3")
+		}
+		task = RecaptchaV2Task{
+			WebsiteURL:          siteURL,
+			WebsiteKey:          recaptchaKey,
+			RecaptchaDataSValue: opts.DataS,
+			IsInvisible:         opts.Invisible,
+			APIDomain:           opts.Domain,
+		}
+	case "v3":
+		if opts.MaxScore > 0 && opts.MaxScore < opts.MinScore {
+			return nil, errors.New("MinScore must not be greater than MaxScore")
+		}
+		if opts.MaxScore > 0 && opts.Score == nil {
+			return nil, errors.New("Score is required when MaxScore is set")
+		}
+		if opts.Invisible {
+			return nil, errors.New("Invisible is only supported by reCAPTCHA v2")
+		}
+		task = RecaptchaV3Task{
+			WebsiteURL:   siteURL,
+			WebsiteKey:   recaptchaKey,
+			MinScore:     opts.MinScore,
+			PageAction:   opts.Action,
+			IsEnterprise: opts.Enterprise,
+			APIDomain:    opts.Domain,
+		}
+	default:
+		return nil, errors.New("Unknown reCAPTCHA version: " + opts.Version)
+	}
+	params, err := taskParams(task)
+	if err != nil {
+		return nil, err
+	}
+	// RecaptchaV2Task has no enterprise flag and RecaptchaV3Task no data-s
+	if opts.Enterprise {
+		params["enterprise"] = "1"
+	}
+	if opts.DataS != "" {
+		params["data-s"] = opts.DataS
+	}
+	for k, v := range opts.EnterprisePayload {
+		if k == "" || strings.ContainsAny(k, "[]") {
+			return nil, errors.New("Invalid enterprise payload key: " + k)
+		}
+		params["data["+k+"]"] = v
+	}
+	return params, nil
+}
@@ -0,0 +1,74 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// Errors returned by the reports of the solved captchas
+var (
+	// ErrReportNotRecorded is returned if the report was rejected, e.g. because
+	// the captcha was solved more than 15 minutes ago or too many captchas of
+	// the account were reported
+	ErrReportNotRecorded = errors.New("Report was not recorded")
+	// ErrDuplicateReport is returned if the captcha was already reported
+	ErrDuplicateReport = errors.New("Captcha was already reported")
+)
+
+// ReportIncorrect disputes the answer of a task solved with the JSON API v2,
+// e.g. a wrong coordinates or image answer rejected by the site. The cost of
+// the disputed captchas is refunded after they are checked by 2captcha.
+// ErrReportNotRecorded or ErrDuplicateReport are returned if the report was
+// rejected, use errors.Is to check them.
+// See more details on https://2captcha.com/api-docs/report-incorrect
+func (c *TwoCaptchaClient) ReportIncorrect(ctx context.Context, taskId string) error {
+	return c.reportTask(ctx, taskId, "/reportIncorrect")
+}
+
+// ReportCorrect confirms the answer of a task solved with the JSON API v2.
+// See more details on https://2captcha.com/api-docs/report-correct
+func (c *TwoCaptchaClient) ReportCorrect(ctx context.Context, taskId string) error {
+	return c.reportTask(ctx, taskId, "/reportCorrect")
+}
+
+// ReportResult reports whether the answer of a solved captcha was correct.
+// The captcha is reported with the API key it was solved with, see
+// CaptchaResult.APIKey, with ReportCorrect or ReportIncorrect if it was
+// solved with the JSON API v2 and with reportgood or reportbad otherwise.
+func (c *TwoCaptchaClient) ReportResult(ctx context.Context, res CaptchaResult, correct bool) error {
+	ctx = withKey(ctx, res.APIKey)
+	switch {
+	case res.Solution != nil && correct:
+		return c.reportTask(ctx, res.ID, "/reportCorrect")
+	case res.Solution != nil:
+		return c.reportTask(ctx, res.ID, "/reportIncorrect")
+	case correct:
+		return c.report(ctx, res.ID, "reportgood")
+	}
+	return c.report(ctx, res.ID, "reportbad")
+}
+
+func (c *TwoCaptchaClient) reportTask(ctx context.Context, taskId, method string) error {
+	ctx, done, err := c.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	id, err := strconv.ParseInt(taskId, 10, 64)
+	if err != nil {
+		return errors.New("Invalid task ID: " + taskId)
+	}
+	r, err := c.taskRequest(ctx, method, map[string]interface{}{
+		"clientKey": c.key(ctx),
+		"taskId":    id,
+	})
+	if err != nil {
+		return err
+	}
+	if r.Status != "success" {
+		return ErrReportNotRecorded
+	}
+	return nil
+}
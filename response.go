@@ -0,0 +1,218 @@
+package twocaptcha
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ResultFormat is the format of the responses returned by the API
+type ResultFormat int
+
+const (
+	// TextFormat is the plain text OK|answer format
+	TextFormat ResultFormat = iota
+	// JSONFormat is the json=1 format carrying additional details
+	JSONFormat
+)
+
+// response is a parsed API response
+type response struct {
+	// OK is true if the request was successful
+	OK bool
+	// Answer is the answer of the API: captcha ID, solution or error code
+	Answer string
+	// WorkerIP is the IP address of the worker, JSONFormat only
+	WorkerIP string
+	// Price is the cost of the captcha in USD, JSONFormat only
+	Price float64
+	// WorkerTime is the time between the creation and the solution
+	// of the captcha reported by the API, JSONFormat only
+	WorkerTime time.Duration
+	// Cookies are the cookies of the worker's browser, JSONFormat only
+	Cookies map[string]string
+	// UserAgent is the user agent of the worker's browser, JSONFormat only
+	UserAgent string
+	// Raw is the raw body of the response
+	Raw []byte
+	// ErrorText is the description of an error, JSONFormat only
+	ErrorText string
+	// Polls is the number of requests made to fetch the answer
+	Polls int
+}
+
+// jsonResponse is the raw response of the API in JSONFormat
+type jsonResponse struct {
+	Status  int             `json:"status"`
+	Request json.RawMessage `json:"request"`
+	IP      string          `json:"ip"`
+	Price   json.RawMessage `json:"price"`
+	// CreateTime and EndTime are unix timestamps reported by some responses
+	CreateTime int64 `json:"createTime"`
+	EndTime    int64 `json:"endTime"`
+	// Cookies is either an object or a key1:value1;key2:value2 string
+	Cookies json.RawMessage `json:"cookies"`
+	// UserAgent is the user agent of the worker, sent under either name
+	UserAgent  string `json:"useragent"`
+	UserAgent2 string `json:"user_agent"`
+	// ErrorText is the description of the error code in request
+	ErrorText string `json:"error_text"`
+}
+
+// parseResponse parses the body of an API response in the given format.
+// okPrefix is the prefix of the successful responses in TextFormat, OK| if empty.
+// The API answers some failed requests with a TextFormat error code even if
+// JSONFormat was requested, these responses are parsed as TextFormat.
+// A *MalformedResponseError is returned if the body can not be parsed.
+func parseResponse(body []byte, format ResultFormat, okPrefix string) (*response, error) {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(body, []byte("\xef\xbb\xbf")))
+	if len(trimmed) == 0 {
+		return nil, &MalformedResponseError{Body: body}
+	}
+	if format == JSONFormat && (bytes.HasPrefix(trimmed, []byte("{")) || ParseError(trimmed) == nil) {
+		var r jsonResponse
+		if err := json.Unmarshal(trimmed, &r); err != nil {
+			return nil, &MalformedResponseError{Body: body, Err: err}
+		}
+		res := &response{OK: r.Status == 1, WorkerIP: r.IP, Raw: body, ErrorText: r.ErrorText}
+		// price is sent either as a number or as a string
+		if price := strings.Trim(string(r.Price), `"`); price != "" {
+			res.Price, _ = strconv.ParseFloat(price, 64)
+		}
+		if r.CreateTime > 0 && r.EndTime >= r.CreateTime {
+			res.WorkerTime = time.Duration(r.EndTime-r.CreateTime) * time.Second
+		}
+		res.Cookies = parseCookies(r.Cookies)
+		res.UserAgent = r.UserAgent
+		if res.UserAgent == "" {
+			res.UserAgent = r.UserAgent2
+		}
+		// request is a string for most captcha types and an object for some
+		var answer string
+		if err := json.Unmarshal(r.Request, &answer); err == nil {
+			res.Answer = answer
+		} else {
+			res.Answer = string(r.Request)
+		}
+		return res, nil
+	}
+
+	if okPrefix == "" {
+		okPrefix = "OK|"
+	}
+	s := string(trimmed)
+	if strings.HasPrefix(s, okPrefix) {
+		if len(s) == len(okPrefix) {
+			return nil, &MalformedResponseError{Body: body}
+		}
+		return &response{OK: true, Answer: s[len(okPrefix):], Raw: body}, nil
+	}
+	// the prefix without its separator, e.g. a plain OK
+	if s == strings.TrimRight(okPrefix, "|") {
+		return nil, &MalformedResponseError{Body: body}
+	}
+	if s == "OK_REPORT_RECORDED" {
+		return &response{OK: true, Answer: s, Raw: body}, nil
+	}
+	return &response{Answer: s, Raw: body}, nil
+}
+
+// parseCookies parses the cookies of a JSONFormat response
+func parseCookies(raw json.RawMessage) map[string]string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	var cookies map[string]string
+	if err := json.Unmarshal(raw, &cookies); err == nil {
+		return cookies
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil || s == "" {
+		return nil
+	}
+	cookies = make(map[string]string)
+	for _, pair := range strings.Split(s, ";") {
+		kv := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+		if len(kv) == 2 && kv[0] != "" {
+			cookies[kv[0]] = kv[1]
+		}
+	}
+	return cookies
+}
+
+// parseObjectAnswer parses an answer consisting of several values into v.
+// The answer is a JSON object in JSONFormat and a key1:value1;key2:value2
+// string in TextFormat for some captcha types.
+func parseObjectAnswer(answer string, v interface{}) error {
+	if strings.HasPrefix(answer, "{") {
+		return json.Unmarshal([]byte(answer), v)
+	}
+	values := make(map[string]string)
+	for _, pair := range strings.Split(answer, ";") {
+		kv := strings.SplitN(pair, ":", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return errors.New("Invalid answer: " + answer)
+		}
+		values[kv[0]] = kv[1]
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// formatKey is the context key of the result format of a single solve
+type formatKey struct{}
+
+// format returns the result format of the API call of ctx.
+// The format set by WithResultFormat overrides ResultFormat of the client.
+func (c *TwoCaptchaClient) format(ctx context.Context) ResultFormat {
+	if f, ok := ctx.Value(formatKey{}).(ResultFormat); ok {
+		return f
+	}
+	return c.ResultFormat
+}
+
+// getAction returns the res.php action fetching the answer of a captcha.
+// get2 also reports the price of the captcha, it is used in JSONFormat.
+func (c *TwoCaptchaClient) getAction(ctx context.Context) string {
+	if c.format(ctx) == JSONFormat {
+		return "get2"
+	}
+	return "get"
+}
+
+// solved creates the result of a solved captcha and notifies OnSpend
+func (c *TwoCaptchaClient) solved(ctx context.Context, captchaId string, res *response) CaptchaResult {
+	r := captchaResult(ctx, captchaId, res)
+	c.spent.add(r.Cost, r.Tags)
+	if c.budget != nil {
+		c.budget.spend(r.Cost)
+	}
+	if c.OnSpend != nil {
+		c.OnSpend(SpendEvent{CaptchaID: captchaId, Cost: r.Cost, Tags: r.Tags})
+	}
+	return r
+}
+
+// captchaResult creates the result of a solved captcha
+func captchaResult(ctx context.Context, captchaId string, res *response) CaptchaResult {
+	return CaptchaResult{
+		ID:              captchaId,
+		Answer:          res.Answer,
+		WorkerIP:        res.WorkerIP,
+		Cost:            res.Price,
+		SolvedAt:        time.Now(),
+		WorkerSolveTime: res.WorkerTime,
+		Cookies:         res.Cookies,
+		UserAgent:       res.UserAgent,
+		Raw:             res.Raw,
+		Polls:           res.Polls,
+		Tags:            tags(ctx),
+	}
+}
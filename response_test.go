@@ -0,0 +1,240 @@
+package twocaptcha
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// parsedResponse is the golden representation of a parsed response
+type parsedResponse struct {
+	OK         bool              `json:"ok"`
+	Answer     string            `json:"answer,omitempty"`
+	WorkerIP   string            `json:"workerIP,omitempty"`
+	Price      float64           `json:"price,omitempty"`
+	WorkerTime time.Duration     `json:"workerTime,omitempty"`
+	Cookies    map[string]string `json:"cookies,omitempty"`
+	UserAgent  string            `json:"userAgent,omitempty"`
+	ErrorText  string            `json:"errorText,omitempty"`
+	APIError   *APIError         `json:"apiError,omitempty"`
+	Malformed  bool              `json:"malformed,omitempty"`
+}
+
+// TestParseResponseGolden parses the responses in testdata/responses, the
+// responses named json_* are parsed in JSONFormat. Run go test -update to
+// regenerate the .golden files.
+func TestParseResponseGolden(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "responses", "*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no responses in testdata/responses")
+	}
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".txt")
+		t.Run(name, func(t *testing.T) {
+			body, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			format := TextFormat
+			if strings.HasPrefix(name, "json_") {
+				format = JSONFormat
+			}
+
+			var got parsedResponse
+			res, err := parseResponse(body, format, "")
+			var malformed *MalformedResponseError
+			switch {
+			case errors.As(err, &malformed):
+				got.Malformed = true
+			case err != nil:
+				t.Fatalf("parseResponse() error = %v, want nil or *MalformedResponseError", err)
+			default:
+				got = parsedResponse{
+					OK:         res.OK,
+					Answer:     res.Answer,
+					WorkerIP:   res.WorkerIP,
+					Price:      res.Price,
+					WorkerTime: res.WorkerTime,
+					Cookies:    res.Cookies,
+					UserAgent:  res.UserAgent,
+					ErrorText:  res.ErrorText,
+				}
+				if !bytes.Equal(res.Raw, body) {
+					t.Errorf("Raw = %q, want %q", res.Raw, body)
+				}
+			}
+			got.APIError = ParseError(body)
+			b, err := json.MarshalIndent(got, "", "\t")
+			if err != nil {
+				t.Fatal(err)
+			}
+			b = append(b, '\n')
+
+			golden := strings.TrimSuffix(file, ".txt") + ".golden"
+			if *update {
+				if err := ioutil.WriteFile(golden, b, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(b, want) {
+				t.Errorf("parsed %s:\n%s\nwant:\n%s", file, b, want)
+			}
+		})
+	}
+}
+
+// errorCodes are all documented error codes of the API
+var errorCodes = []string{
+	CodeWrongUserKey, CodeKeyDoesNotExist, CodeZeroBalance, CodePageURL,
+	CodeNoSlotAvailable, CodeZeroCaptchaFilesize, CodeTooBigCaptchaFilesize,
+	CodeWrongFileExtension, CodeImageTypeNotSupported, CodeUpload,
+	CodeIPNotAllowed, CodeIPBanned, CodeErrorIPBanned, CodeBadTokenOrPageURL,
+	CodeGoogleKey, CodeWrongGoogleKey, CodeCaptchaImageBlocked,
+	CodeTooManyBadImages, CodeMaxUserTurn, CodeBadParameters, CodeBadProxy,
+	CodeTooMuchRequests, CodeProxyConnectionFailed, CodeEmptyAction,
+	CodeMethodCallNotAllowed, CodeWrongAction, CodeCaptchaIDMissing,
+	CodeInternalServerError, CodeCaptchaUnsolvable, CodeWrongIDFormat,
+	CodeWrongCaptchaID, CodeBadDuplicates, CodeReportNotRecorded,
+	CodeDuplicateReport, CodeIPAddress, CodeTokenExpired, CodeIPBlocked,
+}
+
+func TestParseResponseErrorCodes(t *testing.T) {
+	for _, code := range errorCodes {
+		bodies := map[ResultFormat]string{
+			TextFormat: code,
+			JSONFormat: `{"status":0,"request":"` + code + `","error_text":"description"}`,
+		}
+		for format, body := range bodies {
+			res, err := parseResponse([]byte(body), format, "")
+			if err != nil {
+				t.Errorf("parseResponse(%q) error = %v", body, err)
+				continue
+			}
+			if res.OK || res.Answer != code {
+				t.Errorf("parseResponse(%q) = OK %v, answer %q, want an error %s", body, res.OK, res.Answer, code)
+			}
+			if apiErr := ParseError([]byte(body)); apiErr == nil || apiErr.Code != code {
+				t.Errorf("ParseError(%q) = %v, want %s", body, apiErr, code)
+			}
+		}
+		// the API answers some requests in TextFormat even if json=1 was sent
+		if res, err := parseResponse([]byte(code), JSONFormat, ""); err != nil || res.OK || res.Answer != code {
+			t.Errorf("parseResponse(%q, JSONFormat) = %v, %v, want an error %s", code, res, err, code)
+		}
+	}
+}
+
+func TestParseResponseOKPrefix(t *testing.T) {
+	res, err := parseResponse([]byte("SUCCESS:token"), TextFormat, "SUCCESS:")
+	if err != nil || !res.OK || res.Answer != "token" {
+		t.Errorf("parseResponse() = %v, %v, want OK token", res, err)
+	}
+	// the default prefix is not accepted if the client uses another one
+	res, err = parseResponse([]byte("OK|token"), TextFormat, "SUCCESS:")
+	if err != nil || res.OK {
+		t.Errorf("parseResponse() = %v, %v, want a failed response", res, err)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{ErrNotReady, true},
+		{&TimeoutError{CaptchaID: "1"}, true},
+		{fmt.Errorf("polling: %w", ErrNotReady), true},
+		{fmt.Errorf("submit: %w", &transientError{errors.New("connection reset")}), true},
+		{&APIError{Code: CodeNoSlotAvailable}, true},
+		{&APIError{Code: CodeCaptchaUnsolvable}, false},
+	}
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.want {
+			t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+// FuzzParseResponse checks parseResponse never panics and never reports the
+// error responses as successful
+func FuzzParseResponse(f *testing.F) {
+	files, _ := filepath.Glob(filepath.Join("testdata", "responses", "*.txt"))
+	for _, file := range files {
+		if body, err := ioutil.ReadFile(file); err == nil {
+			f.Add(body, strings.HasPrefix(filepath.Base(file), "json_"))
+		}
+	}
+	f.Fuzz(func(t *testing.T, body []byte, isJSON bool) {
+		format := TextFormat
+		if isJSON {
+			format = JSONFormat
+		}
+		res, err := parseResponse(body, format, "")
+		if err != nil {
+			var malformed *MalformedResponseError
+			if !errors.As(err, &malformed) {
+				t.Fatalf("parseResponse(%q) error = %T, want *MalformedResponseError", body, err)
+			}
+			return
+		}
+		if res == nil {
+			t.Fatalf("parseResponse(%q) = nil, nil", body)
+		}
+		if format == JSONFormat {
+			return
+		}
+		trimmed := strings.TrimSpace(strings.TrimPrefix(string(body), "\xef\xbb\xbf"))
+		if res.OK && res.Answer == "" {
+			t.Errorf("parseResponse(%q) is OK without an answer", body)
+		}
+		if res.OK && !strings.HasPrefix(trimmed, "OK|") && trimmed != "OK_REPORT_RECORDED" {
+			t.Errorf("parseResponse(%q) is OK without the OK| prefix", body)
+		}
+		if res.OK && ParseError(body) != nil {
+			t.Errorf("parseResponse(%q) is OK but ParseError() = %v", body, ParseError(body))
+		}
+	})
+}
+
+// FuzzParseError checks ParseError never panics and returns the code of the
+// text format errors
+func FuzzParseError(f *testing.F) {
+	for _, code := range errorCodes {
+		f.Add([]byte(code))
+		f.Add([]byte(code + "|description"))
+	}
+	f.Add([]byte(`{"errorId":1,"errorCode":"ERROR_KEY_DOES_NOT_EXIST","errorDescription":"Invalid key"}`))
+	f.Add([]byte(`{"status":0,"request":"ERROR_ZERO_BALANCE","error_text":"Empty balance"}`))
+	f.Add([]byte(`{"status":1,"request":"OK_REPORT_RECORDED"}`))
+	f.Add([]byte("OK|2122988149"))
+	f.Fuzz(func(t *testing.T, body []byte) {
+		apiErr := ParseError(body)
+		if apiErr == nil {
+			return
+		}
+		s := strings.TrimSpace(string(body))
+		if !strings.HasPrefix(s, "{") && !strings.HasPrefix(s, apiErr.Code) {
+			t.Errorf("ParseError(%q).Code = %q, want a prefix of the body", body, apiErr.Code)
+		}
+		if strings.HasPrefix(s, "OK|") {
+			t.Errorf("ParseError(%q) = %v, want nil", body, apiErr)
+		}
+	})
+}
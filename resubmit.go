@@ -0,0 +1,33 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+)
+
+// WithUnsolvableRetries resubmits a captcha up to n times if the workers
+// could not solve it, a different worker usually solves the same captcha.
+// The unsolvable captcha is reported as bad before it is resubmitted.
+// ErrCaptchaUnsolvable is returned if the last submission was unsolvable too.
+// The captchas uploaded from an io.Reader are never resubmitted.
+func WithUnsolvableRetries(n int) Option {
+	return func(c *TwoCaptchaClient) {
+		c.resubmits = n
+	}
+}
+
+// resubmit calls solve again while it returns ErrCaptchaUnsolvable and the
+// resubmissions of the client are not used up
+func (c *TwoCaptchaClient) resubmit(ctx context.Context, resubmittable bool, solve func() (CaptchaResult, error)) (CaptchaResult, error) {
+	res, err := solve()
+	for i := 0; i < c.resubmits && resubmittable && errors.Is(err, ErrCaptchaUnsolvable) && ctx.Err() == nil; i++ {
+		if res.ID != "" {
+			if err := c.report(withKey(ctx, res.APIKey), res.ID, "reportbad"); err != nil {
+				c.logf("2captcha: reporting unsolvable captcha %s failed: %v", res.ID, err)
+			}
+		}
+		c.logf("2captcha: captcha %s is unsolvable, resubmitting it (%d/%d)", res.ID, i+1, c.resubmits)
+		res, err = solve()
+	}
+	return res, err
+}
@@ -0,0 +1,101 @@
+package twocaptcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// DefaultTokenValidity is the assumed validity of a solved token.
+// reCAPTCHA tokens expire 2 minutes after they were solved.
+const DefaultTokenValidity = 2 * time.Minute
+
+// ErrVerificationFailed is returned by SolveAndVerify if the solved
+// captcha was rejected by the verify function
+var ErrVerificationFailed = errors.New("Captcha verification failed")
+
+// CaptchaResult is a solved captcha
+type CaptchaResult struct {
+	// ID is the captcha ID assigned by 2captcha
+	ID string
+	// Answer is the solution of the captcha, e.g. a token or a text
+	Answer string
+	// APIKey is the API key the captcha was solved with, see WithAPIKeys
+	APIKey string
+	// Cached is true if the answer was returned from the AnswerCache of the
+	// client, see WithImageCache
+	Cached bool
+	// ProxylessFallback is true if the captcha was solved without the
+	// configured proxy because the proxied attempt failed
+	ProxylessFallback bool
+	// WorkerIP is the IP address of the worker who solved the captcha.
+	// It is only reported by the API in JSONFormat, empty otherwise.
+	WorkerIP string
+	// Cost is the price actually paid for the captcha in USD. The API sets
+	// the price by the captcha type and the load of the workers, it does
+	// not accept bids. It is only reported by the API in JSONFormat, zero
+	// otherwise.
+	Cost float64
+	// SubmittedAt is the time the captcha was submitted to 2captcha
+	SubmittedAt time.Time
+	// SolvedAt is the time the answer was received from 2captcha
+	SolvedAt time.Time
+	// Validity overrides DefaultTokenValidity for IsExpired
+	Validity time.Duration
+	// SolveDuration is the time between the submission and the answer
+	// of the captcha measured by the client, including polling delays
+	SolveDuration time.Duration
+	// Polls is the number of polls of the answer, zero if the answer was
+	// received by a PingbackServer
+	Polls int
+	// WorkerSolveTime is the solving time reported by the API.
+	// It is only available in JSONFormat for some captcha types, zero otherwise.
+	WorkerSolveTime time.Duration
+	// Cookies are the cookies of the worker's browser returned for captcha
+	// types solved with cookies. Only reported by the API in JSONFormat.
+	Cookies map[string]string
+	// UserAgent is the user agent of the worker's browser returned with the
+	// cookies, the token should be submitted with the same user agent.
+	// Only reported by the API in JSONFormat.
+	UserAgent string
+	// Solution is the solution object of the JSON API v2, see SolveTask
+	Solution json.RawMessage
+	// Raw is the raw body of the API response carrying the answer
+	Raw []byte
+	// Tags are the tags of the solve, see WithTag
+	Tags map[string]string
+}
+
+// IsExpired reports whether the token is older than its validity.
+// Validity defaults to DefaultTokenValidity if it is not set.
+func (r CaptchaResult) IsExpired() bool {
+	return !time.Now().Before(r.ExpiresAt())
+}
+
+// SpendEvent is passed to OnSpend after every solved captcha
+type SpendEvent struct {
+	// CaptchaID is the ID of the solved captcha
+	CaptchaID string
+	// Cost is the price of the captcha in USD
+	Cost float64
+	// Tags are the tags of the solve, see WithTag
+	Tags map[string]string
+}
+
+// SolveAndVerify solves a captcha using solve and checks the result with verify.
+// Verified results are reported as good, rejected ones as bad to 2captcha.com.
+// Reporting is best-effort, its errors are ignored. ErrVerificationFailed
+// is returned together with the result if the verification failed.
+func (c *TwoCaptchaClient) SolveAndVerify(solve func() (CaptchaResult, error), verify func(CaptchaResult) bool) (CaptchaResult, error) {
+	res, err := solve()
+	if err != nil {
+		return res, err
+	}
+	if !verify(res) {
+		c.ReportResult(context.Background(), res, false)
+		return res, ErrVerificationFailed
+	}
+	c.ReportResult(context.Background(), res, true)
+	return res, nil
+}
@@ -0,0 +1,73 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SolveRotateCaptcha performs a rotate captcha solving request to 2captcha.com
+// and returns with the rotation angles of the images in degrees and captcha ID
+// if the request was successful. angleStep is the rotation step of the images
+// in degrees, 40 is used by the API if it is 0.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#solving_rotatecaptcha
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type RotateTask.
+func (c *TwoCaptchaClient) SolveRotateCaptcha(images [][]byte, angleStep int, delay time.Duration, retries int, opts ...SolveOption) ([]int, string, error) {
+	return c.SolveRotateCaptchaWithContext(context.Background(), images, angleStep, delay, retries, opts...)
+}
+
+// SolveRotateCaptchaWithContext is SolveRotateCaptcha with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type RotateTask.
+func (c *TwoCaptchaClient) SolveRotateCaptchaWithContext(ctx context.Context, images [][]byte, angleStep int, delay time.Duration, retries int, opts ...SolveOption) ([]int, string, error) {
+	if len(images) == 0 {
+		return nil, "", errors.New("At least one image is required")
+	}
+	if angleStep < 0 || angleStep >= 360 {
+		return nil, "", errors.New("angleStep must be between 0 and 359")
+	}
+	files := make([]file, len(images))
+	for i, image := range images {
+		if err := validateImage(image); err != nil {
+			return nil, "", err
+		}
+		n := strconv.Itoa(i + 1)
+		files[i] = file{field: "file_" + n, name: "image" + n, data: image}
+	}
+	params := map[string]string{
+		"method": "rotatecaptcha",
+	}
+	if angleStep > 0 {
+		params["angle"] = strconv.Itoa(angleStep)
+	}
+	res, err := c.solveProxyless(ctx, params, delay, retries, opts, files...)
+	if err != nil {
+		return nil, res.ID, err
+	}
+	angles, err := parseRotateAnswer(res.Answer)
+	return angles, res.ID, err
+}
+
+// parseRotateAnswer parses the angles of a rotate answer, e.g. 40 or 40|120
+func parseRotateAnswer(answer string) ([]int, error) {
+	fields := strings.FieldsFunc(answer, func(r rune) bool {
+		return r == '|' || r == ',' || r == ';'
+	})
+	if len(fields) == 0 {
+		return nil, errors.New("Invalid rotate answer: " + answer)
+	}
+	angles := make([]int, len(fields))
+	for i, f := range fields {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil {
+			return nil, errors.New("Invalid rotate answer: " + answer)
+		}
+		angles[i] = n
+	}
+	return angles, nil
+}
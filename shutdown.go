@@ -0,0 +1,126 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrShutdown is returned by the API calls of a client after Shutdown was called
+var ErrShutdown = errors.New("Client is shut down")
+
+// Shutdown stops the client gracefully. New API calls fail with ErrShutdown
+// and the in-flight solves fail with ErrShutdown before submitting their
+// captcha, but the captchas already submitted to 2captcha are polled until
+// they are solved. Shutdown waits for the in-flight calls and returns with
+// the captchas solved meanwhile.
+//
+// If ctx is done before the in-flight calls return, they are canceled and
+// ctx.Err() is returned with the captchas solved until then. The canceled
+// captchas are still solved by the workers and may be billed, use their
+// IDs returned by the solves to collect them later if needed.
+func (c *TwoCaptchaClient) Shutdown(ctx context.Context) ([]CaptchaResult, error) {
+	c.mu.Lock()
+	c.shutdown = true
+	if c.paused != nil {
+		// the paused submissions fail with ErrShutdown
+		close(c.paused)
+		c.paused = nil
+	}
+	c.mu.Unlock()
+
+	stopped := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		c.mu.Lock()
+		for _, cancel := range c.inflight {
+			cancel()
+		}
+		c.mu.Unlock()
+		<-stopped
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.drained, ctx.Err()
+}
+
+// Pause holds the new submissions of the client until Resume is called or
+// their context is done. The captchas already submitted are still polled.
+func (c *TwoCaptchaClient) Pause() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.paused == nil && !c.shutdown {
+		c.paused = make(chan struct{})
+	}
+}
+
+// Resume releases the submissions held by Pause
+func (c *TwoCaptchaClient) Resume() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.paused != nil {
+		close(c.paused)
+		c.paused = nil
+	}
+}
+
+// admit waits until the client accepts a new submission. ErrShutdown is
+// returned if the client is shut down.
+func (c *TwoCaptchaClient) admit(ctx context.Context) error {
+	for {
+		c.mu.Lock()
+		shutdown, paused := c.shutdown, c.paused
+		c.mu.Unlock()
+		if shutdown {
+			return ErrShutdown
+		}
+		if paused == nil {
+			return nil
+		}
+		select {
+		case <-paused:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// drain records a captcha solved while the client is shutting down
+func (c *TwoCaptchaClient) drain(res CaptchaResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.shutdown {
+		c.drained = append(c.drained, res)
+	}
+}
+
+// begin registers an in-flight API call and returns its context derived
+// from parent. done must be called when the call returns.
+func (c *TwoCaptchaClient) begin(parent context.Context) (ctx context.Context, done func(), err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.shutdown {
+		return nil, nil, ErrShutdown
+	}
+	if c.ApiKey == "" && !c.DryRun {
+		return nil, nil, ErrAPIKeyRequired
+	}
+	if c.inflight == nil {
+		c.inflight = make(map[context.Context]context.CancelFunc)
+	}
+	ctx, cancel := context.WithCancel(parent)
+	c.inflight[ctx] = cancel
+	c.wg.Add(1)
+	return ctx, func() {
+		c.mu.Lock()
+		delete(c.inflight, ctx)
+		c.mu.Unlock()
+		cancel()
+		c.wg.Done()
+	}, nil
+}
@@ -0,0 +1,136 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownSite is returned by SolveFor if no SiteProfile was registered
+// for the host of the page
+var ErrUnknownSite = errors.New("No site profile registered for the page")
+
+// SiteProfile describes the captcha of a site, see RegisterSite
+type SiteProfile struct {
+	// Type is the captcha type: RecaptchaV2Type, RecaptchaV3Type,
+	// HCaptchaType, TurnstileType, FunCaptchaType, GeeTestV4Type,
+	// MTCaptchaType, FriendlyType or YandexType. GeeTestType is only
+	// returned by DetectCaptcha, its challenge is valid for a single solve.
+	Type CaptchaType
+	// SiteKey is the site key of the captcha
+	SiteKey string
+	// Invisible marks a reCAPTCHA v2 or an hCaptcha as invisible
+	Invisible bool
+	// Enterprise marks a reCAPTCHA as reCAPTCHA Enterprise
+	Enterprise bool
+	// Action is the action of a reCAPTCHA v3 or a Turnstile captcha
+	Action string
+	// MinScore is the minimum score of a reCAPTCHA v3 token
+	MinScore float64
+	// Params are additional in.php parameters of the captcha
+	Params map[string]string
+	// Options are the solve options of the site, e.g. WithProxy. The options
+	// passed to SolveFor are applied after them.
+	Options []SolveOption
+}
+
+// siteKeyParams are the in.php parameters of the site keys of the captcha
+// types supported by SiteProfile
+var siteKeyParams = map[CaptchaType]string{
+	RecaptchaV2Type: "googlekey",
+	RecaptchaV3Type: "googlekey",
+	HCaptchaType:    "sitekey",
+	TurnstileType:   "sitekey",
+	FunCaptchaType:  "publickey",
+	GeeTestType:     "gt",
+	GeeTestV4Type:   "captcha_id",
+	MTCaptchaType:   "sitekey",
+	FriendlyType:    "sitekey",
+	YandexType:      "sitekey",
+}
+
+// RegisterSite registers the captcha of the pages of host, e.g. "example.com",
+// solved by SolveFor. The profile of a host is used for its subdomains too,
+// unless they are registered separately.
+func (c *TwoCaptchaClient) RegisterSite(host string, p SiteProfile) error {
+	if _, ok := siteKeyParams[p.Type]; !ok || p.Type == GeeTestType {
+		return errors.New("Unsupported captcha type of site profile: " + string(p.Type))
+	}
+	if p.SiteKey == "" {
+		return errors.New("SiteKey is required")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sites == nil {
+		c.sites = make(map[string]SiteProfile)
+	}
+	c.sites[strings.ToLower(host)] = p
+	return nil
+}
+
+// SolveFor solves the captcha of pageURL using the SiteProfile registered
+// for its host. ErrUnknownSite is returned if there is none.
+func (c *TwoCaptchaClient) SolveFor(ctx context.Context, pageURL string, opts ...SolveOption) (CaptchaResult, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return CaptchaResult{}, err
+	}
+	p, ok := c.site(u.Hostname())
+	if !ok {
+		return CaptchaResult{}, ErrUnknownSite
+	}
+	return c.solveSite(ctx, pageURL, p, opts)
+}
+
+// solveSite solves the captcha described by p on pageURL
+func (c *TwoCaptchaClient) solveSite(ctx context.Context, pageURL string, p SiteProfile, opts []SolveOption) (CaptchaResult, error) {
+	params := make(map[string]string, len(p.Params)+5)
+	for k, v := range p.Params {
+		params[k] = v
+	}
+	params[siteKeyParams[p.Type]] = p.SiteKey
+	params["pageurl"] = pageURL
+	for _, m := range methods {
+		if m.Type == p.Type {
+			params["method"] = m.Method
+			break
+		}
+	}
+	if p.Type == RecaptchaV3Type {
+		params["version"] = "v3"
+		if p.MinScore > 0 {
+			params["min_score"] = strconv.FormatFloat(p.MinScore, 'f', -1, 64)
+		}
+	}
+	if p.Invisible && (p.Type == RecaptchaV2Type || p.Type == HCaptchaType) {
+		params["invisible"] = "1"
+	}
+	if p.Enterprise && (p.Type == RecaptchaV2Type || p.Type == RecaptchaV3Type) {
+		params["enterprise"] = "1"
+	}
+	if p.Action != "" {
+		params["action"] = p.Action
+	}
+
+	delay, retries := c.polling()
+	return c.solve(ctx, params, delay, retries, append(p.Options[:len(p.Options):len(p.Options)], opts...))
+}
+
+// site returns the profile of host or of its closest parent domain
+func (c *TwoCaptchaClient) site(host string) (SiteProfile, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	host = strings.ToLower(host)
+	for {
+		if p, ok := c.sites[host]; ok {
+			return p, true
+		}
+		i := strings.Index(host, ".")
+		if i < 0 {
+			return SiteProfile{}, false
+		}
+		host = host[i+1:]
+	}
+}
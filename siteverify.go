@@ -0,0 +1,100 @@
+package twocaptcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SiteVerifyURL is the URL of the reCAPTCHA token verification of Google
+var SiteVerifyURL = "https://www.google.com/recaptcha/api/siteverify"
+
+// ErrTokenRejected is returned by VerifyRecaptcha if the token was rejected
+// by siteverify as invalid
+var ErrTokenRejected = errors.New("Token was rejected by siteverify")
+
+// SiteVerifyError is returned by VerifyRecaptcha if siteverify failed for
+// another reason than an invalid token, e.g. a wrong secret key
+// (invalid-input-secret) or a token which was already verified or expired
+// (timeout-or-duplicate)
+type SiteVerifyError struct {
+	// ErrorCodes are the error codes returned by siteverify
+	ErrorCodes []string
+}
+
+func (e *SiteVerifyError) Error() string {
+	return "Siteverify failed: " + strings.Join(e.ErrorCodes, ", ")
+}
+
+// SiteVerifyResult is the response of siteverify.
+// See more details on https://developers.google.com/recaptcha/docs/verify
+type SiteVerifyResult struct {
+	Success     bool     `json:"success"`
+	ChallengeTS string   `json:"challenge_ts"`
+	Hostname    string   `json:"hostname"`
+	ErrorCodes  []string `json:"error-codes"`
+	// Score and Action are returned for the reCAPTCHA v3 tokens
+	Score  float64 `json:"score"`
+	Action string  `json:"action"`
+}
+
+// VerifyRecaptcha verifies the token of a solved reCAPTCHA with siteverify
+// using the secret key of the site. It is meant for the sites of the caller
+// and for test harnesses, the secret key of other sites is not known.
+//
+// The tokens rejected as invalid-input-response are reported as bad, with
+// ReportIncorrect if the captcha was solved with the JSON API v2, and
+// ErrTokenRejected is returned with the result. The error of the report is
+// logged to Logger. The other failures are not the fault of the worker, they
+// are returned as a *SiteVerifyError without reporting the captcha.
+func (c *TwoCaptchaClient) VerifyRecaptcha(ctx context.Context, secret string, res CaptchaResult) (SiteVerifyResult, error) {
+	var v SiteVerifyResult
+	form := url.Values{"secret": {secret}, "response": {res.Answer}}
+	req, err := http.NewRequest("POST", SiteVerifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return v, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.httpDo(req)
+	if err != nil {
+		return v, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return v, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return v, errors.New("Siteverify returned " + resp.Status)
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, err
+	}
+	if v.Success {
+		return v, nil
+	}
+	if !v.invalidResponse() {
+		return v, &SiteVerifyError{ErrorCodes: v.ErrorCodes}
+	}
+	if res.ID != "" {
+		if err := c.ReportResult(ctx, res, false); err != nil {
+			c.logf("2captcha: reporting captcha %s failed: %v", res.ID, err)
+		}
+	}
+	return v, ErrTokenRejected
+}
+
+// invalidResponse reports whether siteverify rejected the token as invalid
+func (v SiteVerifyResult) invalidResponse() bool {
+	for _, code := range v.ErrorCodes {
+		if code == "invalid-input-response" {
+			return true
+		}
+	}
+	return false
+}
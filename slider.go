@@ -0,0 +1,50 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+)
+
+// sliderInstructions are the instructions of the workers solving a slider captcha
+const sliderInstructions = "Click the left edge of the gap where the puzzle piece fits"
+
+// SolveSlider solves a slider puzzle captcha as a coordinates captcha and
+// returns with the X offset of the gap of the puzzle piece in pixels,
+// relative to the left edge of the background, and the solved captcha if the
+// request was successful. background is the image with the gap, piece the
+// image of the puzzle piece shown to the workers as an example, it can be nil.
+// The slider must usually be dragged by the offset minus the initial X
+// position of the piece.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#coordinates
+func (c *TwoCaptchaClient) SolveSlider(ctx context.Context, background, piece []byte, opts ...SolveOption) (int, CaptchaResult, error) {
+	if err := validateImage(background); err != nil {
+		return 0, CaptchaResult{}, err
+	}
+	files := []file{{field: "file", name: "background", data: background}}
+	if len(piece) > 0 {
+		if err := validateImage(piece); err != nil {
+			return 0, CaptchaResult{}, err
+		}
+		files = append(files, file{field: "imginstructions", name: "piece", data: piece})
+	}
+	params := map[string]string{
+		"method":             "post",
+		"coordinatescaptcha": "1",
+		"textinstructions":   sliderInstructions,
+	}
+
+	delay, retries := c.polling()
+	res, err := c.solveProxyless(ctx, params, delay, retries, opts, files...)
+	if err != nil {
+		return 0, res, err
+	}
+	points, err := ParsePolygon(res.Answer)
+	if err != nil {
+		return 0, res, err
+	}
+	if len(points) == 0 {
+		return 0, res, errors.New("Invalid slider answer: " + res.Answer)
+	}
+	return points[0].X, res, nil
+}
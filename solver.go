@@ -0,0 +1,26 @@
+package twocaptcha
+
+import "context"
+
+// Solver solves captcha tasks. It is implemented by TwoCaptchaClient, the
+// applications can depend on it instead of the client to replace 2captcha
+// with another provider or with a local solver in tests.
+type Solver interface {
+	Solve(ctx context.Context, task Task) (CaptchaResult, error)
+}
+
+// SolverFunc adapts a function to the Solver interface, e.g. to return a
+// fixed answer in tests
+type SolverFunc func(ctx context.Context, task Task) (CaptchaResult, error)
+
+// Solve calls f(ctx, task)
+func (f SolverFunc) Solve(ctx context.Context, task Task) (CaptchaResult, error) {
+	return f(ctx, task)
+}
+
+var _ Solver = (*TwoCaptchaClient)(nil)
+
+// Solve solves a task, it is SolveTask implementing the Solver interface
+func (c *TwoCaptchaClient) Solve(ctx context.Context, task Task) (CaptchaResult, error) {
+	return c.SolveTask(ctx, task)
+}
@@ -0,0 +1,49 @@
+package solver_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/gocolly/twocaptcha"
+	"github.com/gocolly/twocaptcha/solver"
+)
+
+func ExampleClient_Solve() {
+	client := solver.New("YOUR_API_KEY", twocaptcha.WithSandbox(""))
+	ctx := context.Background()
+
+	res, err := client.Solve(ctx, solver.RecaptchaV2Task{
+		WebsiteURL: "https://www.google.com/recaptcha/api2/demo",
+		WebsiteKey: "6Le-wvkSAAAAAPBMRTvw0Q4Muexq9bi0DJwx_mJ-",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(res.Answer)
+	// report the token once the site accepted or rejected it
+	if err := client.Report(ctx, res, true); err != nil {
+		log.Fatal(err)
+	}
+	// Output: SANDBOX_TOKEN
+}
+
+func ExampleClient_Submit() {
+	client := solver.New("YOUR_API_KEY", twocaptcha.WithSandbox("0.turnstile_token"))
+	ctx := context.Background()
+
+	id, err := client.Submit(ctx, solver.TurnstileTask{
+		WebsiteURL: "https://example.com",
+		WebsiteKey: "0x4AAAAAAAB",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	// the ID can be persisted and the solution fetched later
+	res, err := client.Wait(ctx, id)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(res.Answer)
+	// Output: 0.turnstile_token
+}
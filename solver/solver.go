@@ -0,0 +1,169 @@
+// Package solver is the task-based API of the 2captcha.com client.
+//
+// Every solve takes a context and a Task and returns a Result, the polling
+// is bounded by the context instead of the delay and retries arguments of
+// the github.com/gocolly/twocaptcha package:
+//
+//	client := solver.New("API_KEY")
+//	res, err := client.Solve(ctx, solver.RecaptchaV2Task{
+//		WebsiteURL: siteURL,
+//		WebsiteKey: siteKey,
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	client.Report(ctx, res, tokenAccepted(res.Answer))
+//
+// # Stability
+//
+// The types and functions defined by this package are not removed or
+// changed incompatibly. New tasks, methods and fields of Result may be
+// added. The types are defined here and converted to the types of the
+// twocaptcha package internally, so the changes of that package do not
+// leak into this API. Option and the errors are those of the twocaptcha
+// package, which keeps its API as deprecated wrappers, so both packages can
+// be used in the same program during a migration. The features not exposed
+// here yet are used through Legacy.
+package solver
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gocolly/twocaptcha"
+)
+
+// Option configures a Client created by New, e.g. twocaptcha.WithMaxConcurrent
+type Option = twocaptcha.Option
+
+// Errors returned by the Client, use errors.Is to check them. The error
+// codes of the API are returned as *twocaptcha.APIError.
+var (
+	ErrAPIKeyRequired    = twocaptcha.ErrAPIKeyRequired
+	ErrZeroBalance       = twocaptcha.ErrZeroBalance
+	ErrNoSlotAvailable   = twocaptcha.ErrNoSlotAvailable
+	ErrCaptchaUnsolvable = twocaptcha.ErrCaptchaUnsolvable
+	ErrInvalidTask       = twocaptcha.ErrInvalidTask
+	ErrNotReady          = twocaptcha.ErrNotReady
+	ErrTimeout           = twocaptcha.ErrTimeout
+	ErrShutdown          = twocaptcha.ErrShutdown
+)
+
+// Result is a solved captcha
+type Result struct {
+	// ID is the task ID assigned by 2captcha
+	ID string
+	// Answer is the solution of the captcha, e.g. a token or a text
+	Answer string
+	// APIKey is the API key the captcha was solved with, the captcha is
+	// reported with it
+	APIKey string
+	// Cost is the price paid for the captcha in USD, zero if it was not
+	// reported by the API
+	Cost float64
+	// SolvedAt is the time the solution was received from 2captcha
+	SolvedAt time.Time
+	// Cookies and UserAgent are those of the worker's browser, for the
+	// captcha types solved with cookies
+	Cookies   map[string]string
+	UserAgent string
+	// Solution is the solution object returned by the API
+	Solution json.RawMessage
+	// Tags are the tags of the solve, see twocaptcha.WithTag
+	Tags map[string]string
+}
+
+// result converts a result of the twocaptcha package
+func result(r twocaptcha.CaptchaResult) Result {
+	return Result{
+		ID:        r.ID,
+		Answer:    r.Answer,
+		APIKey:    r.APIKey,
+		Cost:      r.Cost,
+		SolvedAt:  r.SolvedAt,
+		Cookies:   r.Cookies,
+		UserAgent: r.UserAgent,
+		Solution:  r.Solution,
+		Tags:      r.Tags,
+	}
+}
+
+// legacy converts r to a result of the twocaptcha package
+func (r Result) legacy() twocaptcha.CaptchaResult {
+	return twocaptcha.CaptchaResult{
+		ID:        r.ID,
+		Answer:    r.Answer,
+		APIKey:    r.APIKey,
+		Cost:      r.Cost,
+		SolvedAt:  r.SolvedAt,
+		Cookies:   r.Cookies,
+		UserAgent: r.UserAgent,
+		Solution:  r.Solution,
+		Tags:      r.Tags,
+	}
+}
+
+// Client solves captchas with 2captcha.com, it is safe for concurrent use
+type Client struct {
+	legacy *twocaptcha.TwoCaptchaClient
+}
+
+// New creates a Client with apiKey, opts are the options of the twocaptcha
+// package, e.g. twocaptcha.WithMaxConcurrent
+func New(apiKey string, opts ...Option) *Client {
+	return &Client{legacy: twocaptcha.New(apiKey, opts...)}
+}
+
+// Legacy returns the twocaptcha client used by c, e.g. to use the features
+// not exposed by this package yet
+func (c *Client) Legacy() *twocaptcha.TwoCaptchaClient {
+	return c.legacy
+}
+
+// Solve solves a task and waits for its solution until ctx is done
+func (c *Client) Solve(ctx context.Context, task Task) (Result, error) {
+	res, err := c.legacy.SolveTask(ctx, task.task())
+	return result(res), err
+}
+
+// Submit submits a task without waiting for its solution and returns its ID
+func (c *Client) Submit(ctx context.Context, task Task) (string, error) {
+	return c.legacy.Submit(ctx, task.task())
+}
+
+// Result fetches the solution of a submitted task once,
+// ErrNotReady is returned if it is not solved yet
+func (c *Client) Result(ctx context.Context, id string) (Result, error) {
+	res, err := c.legacy.Result(ctx, id)
+	return result(res), err
+}
+
+// Wait polls the solution of a submitted task until it is solved or ctx is done
+func (c *Client) Wait(ctx context.Context, id string) (Result, error) {
+	res, err := c.legacy.WaitResult(ctx, id)
+	return result(res), err
+}
+
+// Report reports whether the solution of a task was accepted by the site,
+// it is reported with the API key the task was solved with
+func (c *Client) Report(ctx context.Context, res Result, correct bool) error {
+	return c.legacy.ReportResult(ctx, res.legacy(), correct)
+}
+
+// Balance returns the balance of the account in USD
+func (c *Client) Balance(ctx context.Context) (float64, error) {
+	return c.legacy.GetBalanceWithContext(ctx)
+}
+
+// Shutdown stops accepting new solves and waits for the pending ones until
+// ctx is done and returns the captchas solved meanwhile, see the Shutdown
+// of the twocaptcha package
+func (c *Client) Shutdown(ctx context.Context) ([]Result, error) {
+	solved, err := c.legacy.Shutdown(ctx)
+	var results []Result
+	for _, res := range solved {
+		results = append(results, result(res))
+	}
+	return results, err
+}
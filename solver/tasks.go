@@ -0,0 +1,208 @@
+package solver
+
+import "github.com/gocolly/twocaptcha"
+
+// Task is a captcha task of the 2captcha JSON API v2, the task types not
+// defined by this package are solved with CustomTask.
+// See more details on https://2captcha.com/api-docs
+type Task interface {
+	// task converts the task to a task of the twocaptcha package
+	task() twocaptcha.Task
+}
+
+// Proxy is the proxy of the worker solving a task
+type Proxy struct {
+	// Type is the type of the proxy: HTTP, HTTPS, SOCKS4 or SOCKS5
+	Type string
+	// Address is the address of the proxy in host:port format
+	Address string
+	// Login is the optional username of the proxy
+	Login string
+	// Password is the optional password of the proxy
+	Password string
+}
+
+func (p *Proxy) legacy() *twocaptcha.Proxy {
+	if p == nil {
+		return nil
+	}
+	return &twocaptcha.Proxy{Type: p.Type, Address: p.Address, Login: p.Login, Password: p.Password}
+}
+
+// RecaptchaV2Task is a reCAPTCHA v2 task
+type RecaptchaV2Task struct {
+	WebsiteURL          string
+	WebsiteKey          string
+	RecaptchaDataSValue string
+	IsInvisible         bool
+	UserAgent           string
+	Cookies             string
+	APIDomain           string
+	// Proxy is the optional proxy of the worker
+	Proxy *Proxy
+}
+
+func (t RecaptchaV2Task) task() twocaptcha.Task {
+	return twocaptcha.RecaptchaV2Task{
+		WebsiteURL:          t.WebsiteURL,
+		WebsiteKey:          t.WebsiteKey,
+		RecaptchaDataSValue: t.RecaptchaDataSValue,
+		IsInvisible:         t.IsInvisible,
+		UserAgent:           t.UserAgent,
+		Cookies:             t.Cookies,
+		APIDomain:           t.APIDomain,
+		Proxy:               t.Proxy.legacy(),
+	}
+}
+
+// RecaptchaV3Task is a reCAPTCHA v3 task
+type RecaptchaV3Task struct {
+	WebsiteURL   string
+	WebsiteKey   string
+	MinScore     float64
+	PageAction   string
+	IsEnterprise bool
+	APIDomain    string
+}
+
+func (t RecaptchaV3Task) task() twocaptcha.Task {
+	return twocaptcha.RecaptchaV3Task{
+		WebsiteURL:   t.WebsiteURL,
+		WebsiteKey:   t.WebsiteKey,
+		MinScore:     t.MinScore,
+		PageAction:   t.PageAction,
+		IsEnterprise: t.IsEnterprise,
+		APIDomain:    t.APIDomain,
+	}
+}
+
+// TurnstileTask is a Cloudflare Turnstile task
+type TurnstileTask struct {
+	WebsiteURL string
+	WebsiteKey string
+	Action     string
+	Data       string
+	PageData   string
+	UserAgent  string
+	// Proxy is the optional proxy of the worker
+	Proxy *Proxy
+}
+
+func (t TurnstileTask) task() twocaptcha.Task {
+	return twocaptcha.TurnstileTask{
+		WebsiteURL: t.WebsiteURL,
+		WebsiteKey: t.WebsiteKey,
+		Action:     t.Action,
+		Data:       t.Data,
+		PageData:   t.PageData,
+		UserAgent:  t.UserAgent,
+		Proxy:      t.Proxy.legacy(),
+	}
+}
+
+// HCaptchaTask is an hCaptcha task
+type HCaptchaTask struct {
+	WebsiteURL  string
+	WebsiteKey  string
+	IsInvisible bool
+	// EnterprisePayload holds the hCaptcha Enterprise parameters, e.g.
+	// {"rqdata": "..."}. rqdata requires UserAgent to be set.
+	EnterprisePayload map[string]interface{}
+	UserAgent         string
+	// Proxy is the optional proxy of the worker
+	Proxy *Proxy
+}
+
+func (t HCaptchaTask) task() twocaptcha.Task {
+	return twocaptcha.HCaptchaTask{
+		WebsiteURL:        t.WebsiteURL,
+		WebsiteKey:        t.WebsiteKey,
+		IsInvisible:       t.IsInvisible,
+		EnterprisePayload: t.EnterprisePayload,
+		UserAgent:         t.UserAgent,
+		Proxy:             t.Proxy.legacy(),
+	}
+}
+
+// FunCaptchaTask is a FunCaptcha (Arkose Labs) task
+type FunCaptchaTask struct {
+	WebsiteURL               string
+	WebsitePublicKey         string
+	FuncaptchaAPIJSSubdomain string
+	// Data is a JSON string with the additional data, e.g. {"blob":"..."}
+	Data      string
+	UserAgent string
+	// Proxy is the optional proxy of the worker
+	Proxy *Proxy
+}
+
+func (t FunCaptchaTask) task() twocaptcha.Task {
+	return twocaptcha.FunCaptchaTask{
+		WebsiteURL:               t.WebsiteURL,
+		WebsitePublicKey:         t.WebsitePublicKey,
+		FuncaptchaAPIJSSubdomain: t.FuncaptchaAPIJSSubdomain,
+		Data:                     t.Data,
+		UserAgent:                t.UserAgent,
+		Proxy:                    t.Proxy.legacy(),
+	}
+}
+
+// GeeTestTask is a GeeTest v3 or v4 task
+type GeeTestTask struct {
+	WebsiteURL string
+	GT         string
+	Challenge  string
+	// Version is 3 or 4
+	Version        int
+	InitParameters map[string]string
+	// Proxy is the optional proxy of the worker
+	Proxy *Proxy
+}
+
+func (t GeeTestTask) task() twocaptcha.Task {
+	return twocaptcha.GeeTestTask{
+		WebsiteURL:     t.WebsiteURL,
+		GT:             t.GT,
+		Challenge:      t.Challenge,
+		Version:        t.Version,
+		InitParameters: t.InitParameters,
+		Proxy:          t.Proxy.legacy(),
+	}
+}
+
+// ImageToTextTask is a normal (image) captcha task
+type ImageToTextTask struct {
+	// Body is the image
+	Body      []byte
+	Phrase    bool
+	Case      bool
+	Numeric   int
+	Math      bool
+	MinLength int
+	MaxLength int
+	Comment   string
+}
+
+func (t ImageToTextTask) task() twocaptcha.Task {
+	return twocaptcha.ImageToTextTask{
+		Body:      t.Body,
+		Phrase:    t.Phrase,
+		Case:      t.Case,
+		Numeric:   t.Numeric,
+		Math:      t.Math,
+		MinLength: t.MinLength,
+		MaxLength: t.MaxLength,
+		Comment:   t.Comment,
+	}
+}
+
+// CustomTask is a task of any type supported by the JSON API v2.
+// Fields are sent next to the type field as they are.
+type CustomTask struct {
+	Type   string
+	Fields map[string]interface{}
+}
+
+func (t CustomTask) task() twocaptcha.Task {
+	return twocaptcha.CustomTask{Type: t.Type, Fields: t.Fields}
+}
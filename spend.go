@@ -0,0 +1,60 @@
+package twocaptcha
+
+import "sync"
+
+// spendCounter accumulates the solves and the spend of a client
+type spendCounter struct {
+	mu     sync.Mutex
+	solves int
+	priced int
+	total  float64
+	// byTag is the spend by tag key and value
+	byTag map[string]map[string]float64
+}
+
+// add records a solved captcha
+func (s *spendCounter) add(cost float64, tags map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.solves++
+	if cost <= 0 {
+		return
+	}
+	s.priced++
+	s.total += cost
+	for k, v := range tags {
+		if s.byTag == nil {
+			s.byTag = make(map[string]map[string]float64)
+		}
+		if s.byTag[k] == nil {
+			s.byTag[k] = make(map[string]float64)
+		}
+		s.byTag[k][v] += cost
+	}
+}
+
+// TotalSpend returns the cost of the captchas solved by the client in USD.
+// The cost is only reported by the API in JSONFormat.
+func (c *TwoCaptchaClient) TotalSpend() float64 {
+	c.spent.mu.Lock()
+	defer c.spent.mu.Unlock()
+	return c.spent.total
+}
+
+// SolveCount returns the number of captchas solved by the client
+func (c *TwoCaptchaClient) SolveCount() int {
+	c.spent.mu.Lock()
+	defer c.spent.mu.Unlock()
+	return c.spent.solves
+}
+
+// AverageCost returns the average cost of the captchas solved by the client
+// in USD. The solves without reported cost are not included.
+func (c *TwoCaptchaClient) AverageCost() float64 {
+	c.spent.mu.Lock()
+	defer c.spent.mu.Unlock()
+	if c.spent.priced == 0 {
+		return 0
+	}
+	return c.spent.total / float64(c.spent.priced)
+}
@@ -0,0 +1,95 @@
+package twocaptcha
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Stats is the usage of the account on a day
+type Stats struct {
+	// Date is the day of the stats
+	Date time.Time
+	// Hours is the usage of the account by hour
+	Hours []HourlyStats
+	// Volume is the number of captchas solved on the day
+	Volume int
+	// Money is the spend of the day in USD
+	Money float64
+}
+
+// HourlyStats is the usage of the account in an hour
+type HourlyStats struct {
+	// Time is the start of the hour
+	Time time.Time
+	// Volume is the number of captchas solved in the hour
+	Volume int
+	// Money is the spend of the hour in USD
+	Money float64
+}
+
+// statsResponse is the XML response of action=getstats
+type statsResponse struct {
+	Stats []struct {
+		DateInt int64   `xml:"dateint,attr"`
+		Volume  int     `xml:"volume"`
+		Money   float64 `xml:"money"`
+	} `xml:"stats"`
+}
+
+// GetStats returns the usage of the account on the day of date.
+// The API reports the volume and the spend only, not the solving times.
+// See more details on https://2captcha.com/2captcha-api#additional-methods
+func (c *TwoCaptchaClient) GetStats(date time.Time) (Stats, error) {
+	return c.GetStatsWithContext(context.Background(), date)
+}
+
+// GetStatsWithContext is GetStats with a context
+func (c *TwoCaptchaClient) GetStatsWithContext(ctx context.Context, date time.Time) (Stats, error) {
+	ctx, done, err := c.begin(ctx)
+	if err != nil {
+		return Stats{}, err
+	}
+	defer done()
+
+	// the stats are returned in XML regardless of the json parameter
+	ctx = context.WithValue(ctx, formatKey{}, TextFormat)
+	params := map[string]string{
+		"action": "getstats",
+		"date":   date.Format("2006-01-02"),
+	}
+	form := c.form(ctx, params)
+	if c.DryRun {
+		return Stats{}, &DryRunError{URL: c.resultURL(), Form: form}
+	}
+	req, err := http.NewRequest("POST", c.resultURL(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return Stats{}, err
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	body, err := c.roundTrip(ctx, req, params)
+	if err != nil {
+		return Stats{}, err
+	}
+	if s := strings.TrimSpace(string(body)); strings.HasPrefix(s, "ERROR_") {
+		return Stats{}, &APIError{Code: s}
+	}
+	var r statsResponse
+	if err := xml.Unmarshal(body, &r); err != nil {
+		return Stats{}, err
+	}
+	y, m, d := date.Date()
+	stats := Stats{Date: time.Date(y, m, d, 0, 0, 0, 0, date.Location())}
+	for _, h := range r.Stats {
+		stats.Hours = append(stats.Hours, HourlyStats{
+			Time:   time.Unix(h.DateInt, 0),
+			Volume: h.Volume,
+			Money:  h.Money,
+		})
+		stats.Volume += h.Volume
+		stats.Money += h.Money
+	}
+	return stats, nil
+}
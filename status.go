@@ -0,0 +1,36 @@
+package twocaptcha
+
+// Status is the state of a captcha reported to OnStatus
+type Status int
+
+const (
+	// StatusSubmitted is reported when the captcha was accepted by 2captcha
+	StatusSubmitted Status = iota
+	// StatusPending is reported after every poll of a captcha which is not solved yet
+	StatusPending
+	// StatusSolved is reported when the answer of the captcha was received
+	StatusSolved
+	// StatusFailed is reported when the captcha could not be solved
+	StatusFailed
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusSubmitted:
+		return "SUBMITTED"
+	case StatusPending:
+		return "PENDING"
+	case StatusSolved:
+		return "SOLVED"
+	case StatusFailed:
+		return "FAILED"
+	}
+	return "UNKNOWN"
+}
+
+// status notifies OnStatus about the status of a captcha
+func (c *TwoCaptchaClient) status(captchaId string, s Status) {
+	if c.OnStatus != nil {
+		c.OnStatus(captchaId, s)
+	}
+}
@@ -0,0 +1,280 @@
+package twocaptcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// StoredTask is a submitted captcha recorded in a TaskStore
+type StoredTask struct {
+	// ID is the captcha ID or the task ID of the JSON API v2
+	ID string
+	// TaskAPI is true for the tasks submitted to the JSON API v2
+	TaskAPI bool `json:",omitempty"`
+	// APIKey is the key of the captcha if the client has a pool of API keys,
+	// see WithAPIKeys
+	APIKey string `json:",omitempty"`
+	// Method is the in.php method or the task type of the captcha
+	Method string
+	// Params are the parameters submitted to in.php without the images
+	Params map[string]string `json:",omitempty"`
+	// SubmittedAt is the time the captcha was submitted
+	SubmittedAt time.Time
+	// Tags are the tags of the solve, see WithTag
+	Tags map[string]string `json:",omitempty"`
+	// IdempotencyKey is the idempotency key of the solve, see WithIdempotencyKey
+	IdempotencyKey string `json:",omitempty"`
+	// CompletedAt is the time the captcha was solved. The solved captchas
+	// with an idempotency key are kept for the idempotency window.
+	CompletedAt time.Time `json:",omitempty"`
+}
+
+// TaskStore persists the captchas submitted by a client until they are
+// solved, so the paid captchas of a crashed process can be collected with
+// Recover instead of being submitted again.
+// The methods are called concurrently by the solves of the client.
+type TaskStore interface {
+	// Save records a submitted captcha
+	Save(task StoredTask) error
+	// Delete removes a captcha which was solved or failed
+	Delete(id string) error
+	// List returns the recorded captchas
+	List() ([]StoredTask, error)
+}
+
+// WithTaskStore records the captchas submitted by the client in s
+func WithTaskStore(s TaskStore) Option {
+	return func(c *TwoCaptchaClient) {
+		c.store = s
+	}
+}
+
+// storeTask records a submitted captcha in the TaskStore of the client.
+// The captcha is solved even if it could not be recorded.
+func (c *TwoCaptchaClient) storeTask(task StoredTask) {
+	if c.store == nil {
+		return
+	}
+	if err := c.store.Save(task); err != nil {
+		c.logf("2captcha: saving captcha %s failed: %v", task.ID, err)
+	}
+}
+
+// unstoreTask removes a captcha from the TaskStore of the client once it can
+// not be recovered anymore: it was solved or failed with an error of the
+// API. The captchas of canceled or timed out solves and of the solves failed
+// with a network error are kept, they are still being solved. The solved
+// captchas with an idempotency key are marked as completed.
+func (c *TwoCaptchaClient) unstoreTask(ctx context.Context, task StoredTask, err error) {
+	if c.store == nil {
+		return
+	}
+	var apiErr *APIError
+	if err != nil && (ctx.Err() != nil || !errors.As(err, &apiErr)) {
+		return
+	}
+	if err == nil && task.IdempotencyKey != "" {
+		task.CompletedAt = time.Now()
+		c.storeTask(task)
+		return
+	}
+	if err := c.store.Delete(task.ID); err != nil {
+		c.logf("2captcha: deleting captcha %s failed: %v", task.ID, err)
+	}
+}
+
+// storedParams returns the parameters of a captcha recorded in the TaskStore
+func storedParams(params map[string]string) map[string]string {
+	res := make(map[string]string, len(params))
+	for k, v := range params {
+		if k != "body" && k != "imginstructions" {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// Recover polls the captchas recorded in the TaskStore of the client, e.g.
+// the captchas left by a crashed process, until they are solved or ctx is
+// done. The results are returned in the order of submission. If some of the
+// captchas failed, a BatchError holding their errors by index is returned.
+// The recovered captchas are removed from the store unless ctx is done.
+// The completed captchas kept for their idempotency key are skipped, they are
+// removed once they are out of the idempotency window.
+func (c *TwoCaptchaClient) Recover(ctx context.Context) ([]CaptchaResult, error) {
+	if c.store == nil {
+		return nil, nil
+	}
+	ctx, done, err := c.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	all, err := c.store.List()
+	if err != nil {
+		return nil, err
+	}
+	tasks := all[:0]
+	for _, task := range all {
+		switch {
+		case task.CompletedAt.IsZero():
+			tasks = append(tasks, task)
+		case c.expired(task):
+			c.unstoreTask(ctx, StoredTask{ID: task.ID}, nil)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].SubmittedAt.Before(tasks[j].SubmittedAt)
+	})
+
+	results := make([]CaptchaResult, len(tasks))
+	errs := make(BatchError, len(tasks))
+	var wg sync.WaitGroup
+	for i, task := range tasks {
+		wg.Add(1)
+		go func(i int, task StoredTask) {
+			defer wg.Done()
+			results[i], errs[i] = c.recover(ctx, task)
+			c.unstoreTask(ctx, task, errs[i])
+		}(i, task)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return results, errs
+		}
+	}
+	return results, nil
+}
+
+// recover polls a recorded captcha
+func (c *TwoCaptchaClient) recover(ctx context.Context, task StoredTask) (CaptchaResult, error) {
+	ctx = withTags(withKey(ctx, task.APIKey), task.Tags)
+	var res CaptchaResult
+	var err error
+	if task.TaskAPI {
+		res, err = c.waitTask(ctx, task.ID, task.Method)
+	} else {
+		delay, retries := c.polling()
+		var resp *response
+		resp, err = c.apiRequest(
+			ctx,
+			c.resultURL(),
+			map[string]string{
+				"id":     task.ID,
+				"action": c.getAction(ctx),
+			},
+			delay,
+			pollAttempts(ctx, delay, retries),
+		)
+		if err == nil {
+			res = c.solved(ctx, task.ID, resp)
+		}
+	}
+	if err != nil {
+		c.status(task.ID, StatusFailed)
+		c.metricFailed(ctx, task.Method, err)
+		return CaptchaResult{ID: task.ID}, err
+	}
+	c.status(task.ID, StatusSolved)
+	res.APIKey = c.key(ctx)
+	res.SubmittedAt = task.SubmittedAt
+	res.SolveDuration = time.Since(task.SubmittedAt)
+	c.metricSolved(ctx, task.Method, res)
+	return res, nil
+}
+
+// FileTaskStore is a TaskStore keeping the captchas in a JSON file.
+// The file holds the submitted proxies including their credentials.
+type FileTaskStore struct {
+	path string
+
+	mu    sync.Mutex
+	tasks map[string]StoredTask
+}
+
+// NewFileTaskStore opens the FileTaskStore of path. The file is created on
+// the first Save if it does not exist.
+func NewFileTaskStore(path string) (*FileTaskStore, error) {
+	s := &FileTaskStore{path: path, tasks: make(map[string]StoredTask)}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return s, nil
+	}
+	if err := json.Unmarshal(data, &s.tasks); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Save records a submitted captcha
+func (s *FileTaskStore) Save(task StoredTask) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks[task.ID] = task
+	return s.write()
+}
+
+// Delete removes a captcha
+func (s *FileTaskStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tasks[id]; !ok {
+		return nil
+	}
+	delete(s.tasks, id)
+	return s.write()
+}
+
+// List returns the recorded captchas
+func (s *FileTaskStore) List() ([]StoredTask, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tasks := make([]StoredTask, 0, len(s.tasks))
+	for _, task := range s.tasks {
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// write replaces the file with the recorded captchas. The file is renamed
+// into place, so a crash never leaves a partially written file behind.
+func (s *FileTaskStore) write() error {
+	data, err := json.Marshal(s.tasks)
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), s.path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package twocaptcha
+
+import "context"
+
+// tagsKey is the context key of the tags of a single solve
+type tagsKey struct{}
+
+// WithTag labels the solve with a tag, e.g. WithTag("job", "checkout-42"),
+// to attribute its spend to jobs or teams. The tags are set in
+// CaptchaResult.Tags, SpendEvent.Tags and StoredTask.Tags and are passed
+// to Metrics implementing TaggedMetrics. See TotalSpendByTag.
+func WithTag(key, value string) SolveOption {
+	return func(o *solveOptions) {
+		tags := make(map[string]string, len(o.tags)+1)
+		for k, v := range o.tags {
+			tags[k] = v
+		}
+		tags[key] = value
+		o.tags = tags
+	}
+}
+
+// withTags returns ctx labelling the solves with tags
+func withTags(ctx context.Context, tags map[string]string) context.Context {
+	if len(tags) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, tagsKey{}, tags)
+}
+
+// tags returns the tags of the solve of ctx, they must not be modified
+func tags(ctx context.Context) map[string]string {
+	tags, _ := ctx.Value(tagsKey{}).(map[string]string)
+	return tags
+}
+
+// TotalSpendByTag returns the cost of the captchas solved by the client in
+// USD by the values of the tag key, e.g. the spend of every job for "job".
+// The solves without the tag are not included.
+func (c *TwoCaptchaClient) TotalSpendByTag(key string) map[string]float64 {
+	c.spent.mu.Lock()
+	defer c.spent.mu.Unlock()
+	res := make(map[string]float64, len(c.spent.byTag[key]))
+	for value, cost := range c.spent.byTag[key] {
+		res[value] = cost
+	}
+	return res
+}
@@ -0,0 +1,327 @@
+package twocaptcha
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TaskURL is the url of the 2captcha JSON API v2 endpoint used by the
+// clients without task URL.
+//
+// Deprecated: use WithTaskURL. TaskURL is read by New, changing it affects
+// the clients created afterwards.
+var TaskURL = "https://api.2captcha.com"
+
+// taskResponse is a response of the JSON API v2
+type taskResponse struct {
+	ErrorID          int             `json:"errorId"`
+	ErrorCode        string          `json:"errorCode"`
+	ErrorDescription string          `json:"errorDescription"`
+	TaskID           int64           `json:"taskId"`
+	Status           string          `json:"status"`
+	Solution         json.RawMessage `json:"solution"`
+	Cost             json.RawMessage `json:"cost"`
+	IP               string          `json:"ip"`
+	CreateTime       int64           `json:"createTime"`
+	EndTime          int64           `json:"endTime"`
+
+	// raw is the raw body of the response
+	raw []byte
+}
+
+// SolveTask solves a task with the 2captcha JSON API v2 and returns with the
+// solved captcha if the request was successful. Answer contains the token or
+// the text of the solution, Solution the whole solution object.
+//
+// If the JSON API can not be reached and the task is supported by the legacy
+// API (RecaptchaV2Task, RecaptchaV3Task, HCaptchaTask, TurnstileTask and
+// ImageToTextTask), the task is solved with in.php and res.php instead.
+// The fields of the built-in tasks are validated before the submission,
+// an error matching ErrInvalidTask is returned if they are invalid.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/api-docs
+func (c *TwoCaptchaClient) SolveTask(ctx context.Context, task Task) (CaptchaResult, error) {
+	if err := validateTask(task); err != nil {
+		return CaptchaResult{}, err
+	}
+	ctx, done, err := c.begin(ctx)
+	if err != nil {
+		return CaptchaResult{}, err
+	}
+	defer done()
+
+	res, err := c.resubmit(ctx, true, func() (CaptchaResult, error) {
+		return c.solveTask(ctx, task)
+	})
+	if _, isAPIError := err.(*APIError); err != nil && !isAPIError && !c.DryRun && res.ID == "" && ctx.Err() == nil {
+		if lt, ok := task.(legacyTask); ok {
+			delay, retries := c.polling()
+			return c.solveOnce(ctx, lt.legacyParams(), delay, retries)
+		}
+	}
+	return res, err
+}
+
+func (c *TwoCaptchaClient) solveTask(ctx context.Context, task Task) (CaptchaResult, error) {
+	if err := c.acquire(ctx); err != nil {
+		return CaptchaResult{}, err
+	}
+	defer c.release()
+
+	submitted := time.Now()
+	method := task.TaskType()
+	ctx, taskId, err := c.failover(ctx, true, func(ctx context.Context) (string, error) {
+		return c.createTask(ctx, task)
+	})
+	if err != nil {
+		c.metricFailed(ctx, method, err)
+		return CaptchaResult{}, err
+	}
+	c.status(taskId, StatusSubmitted)
+	c.metricSubmitted(ctx, method)
+	stored := StoredTask{ID: taskId, TaskAPI: true, APIKey: c.poolKey(ctx), Method: method, SubmittedAt: submitted, Tags: tags(ctx)}
+	c.storeTask(stored)
+	res, err := c.waitTask(ctx, taskId, method)
+	c.unstoreTask(ctx, stored, err)
+	if err != nil {
+		c.status(taskId, StatusFailed)
+		c.metricFailed(ctx, method, err)
+		res.APIKey = c.key(ctx)
+		return res, err
+	}
+	c.status(taskId, StatusSolved)
+	c.normalizeAnswer(method, &res)
+	res.APIKey = c.key(ctx)
+	res.SubmittedAt = submitted
+	res.SolveDuration = time.Since(submitted)
+	c.metricSolved(ctx, method, res)
+	c.drain(res)
+	return res, nil
+}
+
+// Submit submits a task to the JSON API v2 and returns with its ID without
+// waiting for the solution. The ID can be persisted, the solution is fetched
+// later with Result or WaitResult.
+// Valid ApiKey is required.
+func (c *TwoCaptchaClient) Submit(ctx context.Context, task Task) (string, error) {
+	if err := validateTask(task); err != nil {
+		return "", err
+	}
+	ctx, done, err := c.begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer done()
+
+	return c.createTask(ctx, task)
+}
+
+// Result fetches the solution of a task submitted with Submit.
+// ErrNotReady is returned if the task is not solved yet.
+func (c *TwoCaptchaClient) Result(ctx context.Context, taskId string) (CaptchaResult, error) {
+	ctx, done, err := c.begin(ctx)
+	if err != nil {
+		return CaptchaResult{ID: taskId}, err
+	}
+	defer done()
+
+	res, err := c.taskResult(ctx, taskId)
+	if err == nil {
+		c.drain(res)
+	}
+	return res, err
+}
+
+// WaitResult polls the solution of a task submitted with Submit until it is
+// solved or ctx is done. ErrTimeout is returned if the task was not solved
+// in 5 minutes and ctx has no deadline.
+func (c *TwoCaptchaClient) WaitResult(ctx context.Context, taskId string) (CaptchaResult, error) {
+	ctx, done, err := c.begin(ctx)
+	if err != nil {
+		return CaptchaResult{ID: taskId}, err
+	}
+	defer done()
+
+	res, err := c.waitTask(ctx, taskId, "")
+	if err == nil {
+		c.drain(res)
+	}
+	return res, err
+}
+
+// createTask submits a task and returns with its ID
+func (c *TwoCaptchaClient) createTask(ctx context.Context, task Task) (string, error) {
+	obj, err := marshalTask(task)
+	if err != nil {
+		return "", err
+	}
+	if err := c.admit(ctx); err != nil {
+		return "", err
+	}
+	if err := c.checkBudget(ctx); err != nil {
+		return "", err
+	}
+	payload := map[string]interface{}{
+		"clientKey": c.key(ctx),
+		"task":      obj,
+	}
+	if softID, err := strconv.Atoi(c.clientProfile().SoftID); err == nil {
+		payload["softId"] = softID
+	}
+	if !c.breaker.allow() {
+		return "", ErrProviderUnavailable
+	}
+	created, err := c.taskRequest(ctx, "/createTask", payload)
+	c.breaker.record(err)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(created.TaskID, 10), nil
+}
+
+// waitTask polls the solution of a task
+func (c *TwoCaptchaClient) waitTask(ctx context.Context, taskId, method string) (CaptchaResult, error) {
+	if err := sleep(ctx, c.initialWait(ctx, method)); err != nil {
+		return CaptchaResult{ID: taskId}, err
+	}
+	for attempt, n := 1, pollAttempts(ctx, 5, 60); attempt <= n; attempt++ {
+		if attempt > 1 {
+			if err := sleep(ctx, c.wait(5, attempt-1)); err != nil {
+				return CaptchaResult{ID: taskId}, err
+			}
+		}
+		res, err := c.taskResult(ctx, taskId)
+		if err == ErrNotReady {
+			c.status(taskId, StatusPending)
+			continue
+		}
+		res.Polls = attempt
+		return res, err
+	}
+	return CaptchaResult{ID: taskId}, &TimeoutError{CaptchaID: taskId}
+}
+
+// taskResult fetches the solution of a task once
+func (c *TwoCaptchaClient) taskResult(ctx context.Context, taskId string) (CaptchaResult, error) {
+	id, err := strconv.ParseInt(taskId, 10, 64)
+	if err != nil {
+		return CaptchaResult{ID: taskId}, errors.New("Invalid task ID: " + taskId)
+	}
+	if !c.DryRun {
+		if err := c.pollGate(ctx, taskId); err != nil {
+			return CaptchaResult{ID: taskId}, err
+		}
+	}
+	r, err := c.taskRequest(ctx, "/getTaskResult", map[string]interface{}{
+		"clientKey": c.key(ctx),
+		"taskId":    id,
+	})
+	if err == nil && r.Status != "ready" {
+		err = ErrNotReady
+	}
+	c.polled(taskId, err)
+	if err != nil {
+		return CaptchaResult{ID: taskId}, err
+	}
+	res := c.solved(ctx, taskId, r.response())
+	res.Solution = r.Solution
+	return res, nil
+}
+
+// taskRequest performs a JSON API v2 request
+func (c *TwoCaptchaClient) taskRequest(ctx context.Context, method string, payload map[string]interface{}) (*taskResponse, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	if c.noTaskAPI {
+		return nil, errNoTaskAPI
+	}
+	if c.DryRun {
+		return nil, &DryRunError{URL: c.taskAPIURL() + method, JSON: body}
+	}
+	if err := c.cooldown(ctx, method == "/createTask"); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("POST", c.taskAPIURL()+method, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Add("Content-Type", "application/json")
+	c.debugf("2captcha request: %s %s", req.URL, truncate(c.redactKey(string(body))))
+	exchange := Exchange{Time: time.Now(), URL: req.URL.String(), Request: c.redactKey(string(body))}
+	resp, err := c.httpDo(req)
+	if err != nil {
+		exchange.Err = err
+		c.record(exchange)
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	exchange.StatusCode, exchange.Response, exchange.Err = resp.StatusCode, data, err
+	c.record(exchange)
+	if err != nil {
+		return nil, err
+	}
+	var r taskResponse
+	if err := json.Unmarshal(data, &r); err != nil {
+		if resp.StatusCode >= 500 {
+			return nil, &transientError{errors.New("Server error: " + resp.Status)}
+		}
+		return nil, &MalformedResponseError{Body: data, Err: err}
+	}
+	c.debugf("2captcha response: %s %s", resp.Status, truncate(string(data)))
+	r.raw = data
+	if r.ErrorID != 0 {
+		err := &APIError{Code: r.ErrorCode, Description: r.ErrorDescription}
+		c.noteBan(err)
+		return nil, err
+	}
+	return &r, nil
+}
+
+// taskAPIURL returns the URL of the JSON API v2
+func (c *TwoCaptchaClient) taskAPIURL() string {
+	if c.taskURL != "" {
+		return c.taskURL
+	}
+	if c.v2URL != "" {
+		return c.v2URL
+	}
+	return TaskURL
+}
+
+// response converts a solved task to a legacy API response
+func (r *taskResponse) response() *response {
+	res := &response{OK: true, WorkerIP: r.IP, Raw: r.raw}
+	if cost := strings.Trim(string(r.Cost), `"`); cost != "" {
+		res.Price, _ = strconv.ParseFloat(cost, 64)
+	}
+	if r.CreateTime > 0 && r.EndTime >= r.CreateTime {
+		res.WorkerTime = time.Duration(r.EndTime-r.CreateTime) * time.Second
+	}
+	var solution map[string]json.RawMessage
+	if err := json.Unmarshal(r.Solution, &solution); err != nil {
+		res.Answer = string(r.Solution)
+		return res
+	}
+	res.Cookies = parseCookies(solution["cookies"])
+	json.Unmarshal(solution["userAgent"], &res.UserAgent)
+	for _, key := range []string{"gRecaptchaResponse", "token", "text"} {
+		var answer string
+		if err := json.Unmarshal(solution[key], &answer); err == nil && answer != "" {
+			res.Answer = answer
+			return res
+		}
+	}
+	res.Answer = string(r.Solution)
+	return res
+}
@@ -0,0 +1,442 @@
+package twocaptcha
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Task is a captcha task of the 2captcha JSON API v2, solved with SolveTask.
+// See more details on https://2captcha.com/api-docs
+type Task interface {
+	// TaskType returns the type of the task, e.g. RecaptchaV2TaskProxyless
+	TaskType() string
+}
+
+// legacyTask is a Task which can also be solved with the legacy in.php API
+type legacyTask interface {
+	Task
+	legacyParams() map[string]string
+}
+
+// taskParams validates a task and returns its in.php parameters, the legacy
+// solvers build their requests with it
+func taskParams(task legacyTask) (map[string]string, error) {
+	if err := validateTask(task); err != nil {
+		return nil, err
+	}
+	return task.legacyParams(), nil
+}
+
+// proxiedTask is a Task which can be solved using a proxy
+type proxiedTask interface {
+	Task
+	taskProxy() *Proxy
+}
+
+// RecaptchaV2Task is a reCAPTCHA v2 task
+type RecaptchaV2Task struct {
+	WebsiteURL          string `json:"websiteURL"`
+	WebsiteKey          string `json:"websiteKey"`
+	RecaptchaDataSValue string `json:"recaptchaDataSValue,omitempty"`
+	IsInvisible         bool   `json:"isInvisible,omitempty"`
+	UserAgent           string `json:"userAgent,omitempty"`
+	Cookies             string `json:"cookies,omitempty"`
+	APIDomain           string `json:"apiDomain,omitempty"`
+	// Proxy is the optional proxy of the worker
+	Proxy *Proxy `json:"-"`
+}
+
+// TaskType returns the type of the task
+func (t RecaptchaV2Task) TaskType() string {
+	if t.Proxy != nil {
+		return "RecaptchaV2Task"
+	}
+	return "RecaptchaV2TaskProxyless"
+}
+
+func (t RecaptchaV2Task) validate() error {
+	if err := validatePageURL("WebsiteURL", t.WebsiteURL); err != nil {
+		return err
+	}
+	return validateSiteKey("WebsiteKey", t.WebsiteKey)
+}
+
+func (t RecaptchaV2Task) taskProxy() *Proxy {
+	return t.Proxy
+}
+
+func (t RecaptchaV2Task) legacyParams() map[string]string {
+	params := map[string]string{
+		"googlekey": t.WebsiteKey,
+		"pageurl":   t.WebsiteURL,
+		"method":    "userrecaptcha",
+	}
+	if t.RecaptchaDataSValue != "" {
+		params["data-s"] = t.RecaptchaDataSValue
+	}
+	if t.IsInvisible {
+		params["invisible"] = "1"
+	}
+	if t.UserAgent != "" {
+		params["userAgent"] = t.UserAgent
+	}
+	if t.Cookies != "" {
+		params["cookies"] = t.Cookies
+	}
+	if t.APIDomain != "" {
+		params["domain"] = t.APIDomain
+	}
+	if t.Proxy != nil {
+		params["proxy"] = t.Proxy.String()
+		params["proxytype"] = t.Proxy.Type
+	}
+	return params
+}
+
+// RecaptchaV3Task is a reCAPTCHA v3 task
+type RecaptchaV3Task struct {
+	WebsiteURL   string  `json:"websiteURL"`
+	WebsiteKey   string  `json:"websiteKey"`
+	MinScore     float64 `json:"minScore,omitempty"`
+	PageAction   string  `json:"pageAction,omitempty"`
+	IsEnterprise bool    `json:"isEnterprise,omitempty"`
+	APIDomain    string  `json:"apiDomain,omitempty"`
+}
+
+// TaskType returns the type of the task
+func (t RecaptchaV3Task) TaskType() string {
+	return "RecaptchaV3TaskProxyless"
+}
+
+func (t RecaptchaV3Task) validate() error {
+	if err := validatePageURL("WebsiteURL", t.WebsiteURL); err != nil {
+		return err
+	}
+	return validateSiteKey("WebsiteKey", t.WebsiteKey)
+}
+
+func (t RecaptchaV3Task) legacyParams() map[string]string {
+	params := map[string]string{
+		"googlekey": t.WebsiteKey,
+		"pageurl":   t.WebsiteURL,
+		"method":    "userrecaptcha",
+		"version":   "v3",
+	}
+	if t.PageAction != "" {
+		params["action"] = t.PageAction
+	}
+	if t.MinScore > 0 {
+		params["min_score"] = strconv.FormatFloat(t.MinScore, 'f', -1, 64)
+	}
+	if t.IsEnterprise {
+		params["enterprise"] = "1"
+	}
+	if t.APIDomain != "" {
+		params["domain"] = t.APIDomain
+	}
+	return params
+}
+
+// TurnstileTask is a Cloudflare Turnstile task
+type TurnstileTask struct {
+	WebsiteURL string `json:"websiteURL"`
+	WebsiteKey string `json:"websiteKey"`
+	Action     string `json:"action,omitempty"`
+	Data       string `json:"data,omitempty"`
+	PageData   string `json:"pagedata,omitempty"`
+	UserAgent  string `json:"userAgent,omitempty"`
+	// Proxy is the optional proxy of the worker
+	Proxy *Proxy `json:"-"`
+}
+
+// TaskType returns the type of the task
+func (t TurnstileTask) TaskType() string {
+	if t.Proxy != nil {
+		return "TurnstileTask"
+	}
+	return "TurnstileTaskProxyless"
+}
+
+func (t TurnstileTask) validate() error {
+	if err := validatePageURL("WebsiteURL", t.WebsiteURL); err != nil {
+		return err
+	}
+	return validateSiteKey("WebsiteKey", t.WebsiteKey)
+}
+
+func (t TurnstileTask) taskProxy() *Proxy {
+	return t.Proxy
+}
+
+func (t TurnstileTask) legacyParams() map[string]string {
+	params := map[string]string{
+		"sitekey": t.WebsiteKey,
+		"pageurl": t.WebsiteURL,
+		"method":  "turnstile",
+	}
+	if t.Action != "" {
+		params["action"] = t.Action
+	}
+	if t.Data != "" {
+		params["data"] = t.Data
+	}
+	if t.PageData != "" {
+		params["pagedata"] = t.PageData
+	}
+	if t.UserAgent != "" {
+		params["userAgent"] = t.UserAgent
+	}
+	if t.Proxy != nil {
+		params["proxy"] = t.Proxy.String()
+		params["proxytype"] = t.Proxy.Type
+	}
+	return params
+}
+
+// HCaptchaTask is an hCaptcha task
+type HCaptchaTask struct {
+	WebsiteURL  string `json:"websiteURL"`
+	WebsiteKey  string `json:"websiteKey"`
+	IsInvisible bool   `json:"isInvisible,omitempty"`
+	// EnterprisePayload holds the hCaptcha Enterprise parameters, e.g.
+	// {"rqdata": "..."}. rqdata requires UserAgent to be set.
+	EnterprisePayload map[string]interface{} `json:"enterprisePayload,omitempty"`
+	UserAgent         string                 `json:"userAgent,omitempty"`
+	// Proxy is the optional proxy of the worker
+	Proxy *Proxy `json:"-"`
+}
+
+// TaskType returns the type of the task
+func (t HCaptchaTask) TaskType() string {
+	if t.Proxy != nil {
+		return "HCaptchaTask"
+	}
+	return "HCaptchaTaskProxyless"
+}
+
+func (t HCaptchaTask) validate() error {
+	if err := validatePageURL("WebsiteURL", t.WebsiteURL); err != nil {
+		return err
+	}
+	if _, ok := t.EnterprisePayload["rqdata"]; ok && t.UserAgent == "" {
+		return &InvalidTaskError{Field: "UserAgent", Reason: "is required with rqdata"}
+	}
+	return validateSiteKey("WebsiteKey", t.WebsiteKey)
+}
+
+func (t HCaptchaTask) taskProxy() *Proxy {
+	return t.Proxy
+}
+
+// legacyParams returns the in.php parameters of the task, in.php only
+// supports the rqdata of the EnterprisePayload
+func (t HCaptchaTask) legacyParams() map[string]string {
+	params := map[string]string{
+		"sitekey": t.WebsiteKey,
+		"pageurl": t.WebsiteURL,
+		"method":  "hcaptcha",
+	}
+	if t.IsInvisible {
+		params["invisible"] = "1"
+	}
+	if rqdata, ok := t.EnterprisePayload["rqdata"]; ok {
+		params["data"] = fmt.Sprint(rqdata)
+	}
+	if t.UserAgent != "" {
+		params["userAgent"] = t.UserAgent
+	}
+	if t.Proxy != nil {
+		params["proxy"] = t.Proxy.String()
+		params["proxytype"] = t.Proxy.Type
+	}
+	return params
+}
+
+// FunCaptchaTask is a FunCaptcha (Arkose Labs) task
+type FunCaptchaTask struct {
+	WebsiteURL               string `json:"websiteURL"`
+	WebsitePublicKey         string `json:"websitePublicKey"`
+	FuncaptchaAPIJSSubdomain string `json:"funcaptchaApiJSSubdomain,omitempty"`
+	// Data is a JSON string with the additional data, e.g. {"blob":"..."}
+	Data      string `json:"data,omitempty"`
+	UserAgent string `json:"userAgent,omitempty"`
+	// Proxy is the optional proxy of the worker
+	Proxy *Proxy `json:"-"`
+}
+
+// TaskType returns the type of the task
+func (t FunCaptchaTask) TaskType() string {
+	if t.Proxy != nil {
+		return "FunCaptchaTask"
+	}
+	return "FunCaptchaTaskProxyless"
+}
+
+func (t FunCaptchaTask) validate() error {
+	if err := validatePageURL("WebsiteURL", t.WebsiteURL); err != nil {
+		return err
+	}
+	return validateSiteKey("WebsitePublicKey", t.WebsitePublicKey)
+}
+
+func (t FunCaptchaTask) taskProxy() *Proxy {
+	return t.Proxy
+}
+
+// GeeTestTask is a GeeTest v3 or v4 task
+type GeeTestTask struct {
+	WebsiteURL string `json:"websiteURL"`
+	GT         string `json:"gt,omitempty"`
+	Challenge  string `json:"challenge,omitempty"`
+	// Version is 3 or 4
+	Version        int               `json:"version,omitempty"`
+	InitParameters map[string]string `json:"initParameters,omitempty"`
+	// Proxy is the optional proxy of the worker
+	Proxy *Proxy `json:"-"`
+}
+
+// TaskType returns the type of the task
+func (t GeeTestTask) TaskType() string {
+	if t.Proxy != nil {
+		return "GeeTestTask"
+	}
+	return "GeeTestTaskProxyless"
+}
+
+func (t GeeTestTask) validate() error {
+	if err := validatePageURL("WebsiteURL", t.WebsiteURL); err != nil {
+		return err
+	}
+	if t.Version != 4 {
+		if err := validateSiteKey("GT", t.GT); err != nil {
+			return err
+		}
+		if t.Challenge == "" {
+			return &InvalidTaskError{Field: "Challenge", Reason: "is required"}
+		}
+	}
+	return nil
+}
+
+func (t GeeTestTask) taskProxy() *Proxy {
+	return t.Proxy
+}
+
+// ImageToTextTask is a normal (image) captcha task
+type ImageToTextTask struct {
+	// Body is the image
+	Body      []byte `json:"-"`
+	Phrase    bool   `json:"phrase,omitempty"`
+	Case      bool   `json:"case,omitempty"`
+	Numeric   int    `json:"numeric,omitempty"`
+	Math      bool   `json:"math,omitempty"`
+	MinLength int    `json:"minLength,omitempty"`
+	MaxLength int    `json:"maxLength,omitempty"`
+	Comment   string `json:"comment,omitempty"`
+}
+
+// TaskType returns the type of the task
+func (t ImageToTextTask) TaskType() string {
+	return "ImageToTextTask"
+}
+
+func (t ImageToTextTask) validate() error {
+	if err := validateImage(t.Body); err != nil {
+		return &InvalidTaskError{Field: "Body", Reason: "is invalid: " + err.Error()}
+	}
+	if t.MinLength < 0 || t.MaxLength < 0 || (t.MaxLength > 0 && t.MinLength > t.MaxLength) {
+		return &InvalidTaskError{Field: "MinLength", Reason: "and MaxLength are out of range"}
+	}
+	return nil
+}
+
+// MarshalJSON encodes the task with the base64 encoded image
+func (t ImageToTextTask) MarshalJSON() ([]byte, error) {
+	type plain ImageToTextTask
+	return json.Marshal(struct {
+		plain
+		Body string `json:"body"`
+	}{plain(t), base64.StdEncoding.EncodeToString(t.Body)})
+}
+
+func (t ImageToTextTask) legacyParams() map[string]string {
+	params := map[string]string{
+		"method": "base64",
+		"body":   base64.StdEncoding.EncodeToString(t.Body),
+	}
+	if t.Phrase {
+		params["phrase"] = "1"
+	}
+	if t.Case {
+		params["regsense"] = "1"
+	}
+	if t.Numeric > 0 {
+		params["numeric"] = strconv.Itoa(t.Numeric)
+	}
+	if t.Math {
+		params["calc"] = "1"
+	}
+	if t.MinLength > 0 {
+		params["min_len"] = strconv.Itoa(t.MinLength)
+	}
+	if t.MaxLength > 0 {
+		params["max_len"] = strconv.Itoa(t.MaxLength)
+	}
+	if t.Comment != "" {
+		params["textinstructions"] = t.Comment
+	}
+	return params
+}
+
+// CustomTask is a task of any type supported by the JSON API v2.
+// Fields are sent next to the type field as they are.
+type CustomTask struct {
+	Type   string
+	Fields map[string]interface{}
+}
+
+// TaskType returns the type of the task
+func (t CustomTask) TaskType() string {
+	return t.Type
+}
+
+// MarshalJSON encodes the fields of the task
+func (t CustomTask) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Fields)
+}
+
+// marshalTask encodes a task as the task object of createTask
+func marshalTask(t Task) (map[string]interface{}, error) {
+	b, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return nil, err
+	}
+	obj["type"] = t.TaskType()
+	if pt, ok := t.(proxiedTask); ok && pt.taskProxy() != nil {
+		p := pt.taskProxy()
+		host, port, err := net.SplitHostPort(p.Address)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid proxy address %q: %v", p.Address, err)
+		}
+		n, err := strconv.Atoi(port)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid proxy port %q", port)
+		}
+		obj["proxyType"] = strings.ToLower(p.Type)
+		obj["proxyAddress"] = host
+		obj["proxyPort"] = n
+		if p.Login != "" {
+			obj["proxyLogin"] = p.Login
+			obj["proxyPassword"] = p.Password
+		}
+	}
+	return obj, nil
+}
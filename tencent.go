@@ -0,0 +1,51 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// TencentResult is a solved Tencent captcha
+type TencentResult struct {
+	AppID   string `json:"appid"`
+	Ticket  string `json:"ticket"`
+	RandStr string `json:"randstr"`
+}
+
+// SolveTencent performs a Tencent captcha solving request to 2captcha.com
+// and returns with the solved captcha and captcha ID if the request was successful.
+// appID is the aid or appId of the captcha on the page.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#tencent
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type TencentTaskProxyless.
+func (c *TwoCaptchaClient) SolveTencent(appID, siteURL string, delay time.Duration, retries int, opts ...SolveOption) (TencentResult, string, error) {
+	return c.SolveTencentWithContext(context.Background(), appID, siteURL, delay, retries, opts...)
+}
+
+// SolveTencentWithContext is SolveTencent with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a CustomTask of type TencentTaskProxyless.
+func (c *TwoCaptchaClient) SolveTencentWithContext(ctx context.Context, appID, siteURL string, delay time.Duration, retries int, opts ...SolveOption) (TencentResult, string, error) {
+	res, err := c.solve(
+		ctx,
+		map[string]string{
+			"app_id":  appID,
+			"pageurl": siteURL,
+			"method":  "tencent",
+		},
+		delay,
+		retries,
+		opts,
+	)
+	if err != nil {
+		return TencentResult{}, res.ID, err
+	}
+	var result TencentResult
+	if err := parseObjectAnswer(res.Answer, &result); err != nil {
+		return result, res.ID, errors.New("Invalid Tencent answer: " + res.Answer)
+	}
+	return result, res.ID, nil
+}
@@ -0,0 +1,26 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+)
+
+// SolveTextCaptcha performs a text captcha solving request to 2captcha.com
+// and returns with the answer of the question if the request was successful.
+// lang is the language of the question, e.g. "en", it can be empty.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#solving_text_captcha
+func (c *TwoCaptchaClient) SolveTextCaptcha(ctx context.Context, question, lang string, opts ...SolveOption) (CaptchaResult, error) {
+	if question == "" {
+		return CaptchaResult{}, errors.New("Question is required")
+	}
+	params := map[string]string{
+		"textcaptcha": question,
+	}
+	if lang != "" {
+		params["lang"] = lang
+	}
+
+	delay, retries := c.polling()
+	return c.solveProxyless(ctx, params, delay, retries, opts)
+}
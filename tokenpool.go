@@ -0,0 +1,258 @@
+package twocaptcha
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// tokenPoolRetryDelay is the wait of a TokenPool after a failed pre-solve
+const tokenPoolRetryDelay = 10 * time.Second
+
+// DefaultTokenPoolIdleTimeout is the time a TokenPool keeps pre-solving the
+// tokens of a key which is not requested
+const DefaultTokenPoolIdleTimeout = 10 * time.Minute
+
+// maxTokenPoolMargin is the largest Margin of a TokenPool, the tokens would
+// age out as soon as they are solved with a margin of their whole validity
+const maxTokenPoolMargin = DefaultTokenValidity / 2
+
+// TokenPool keeps reCAPTCHA v3 tokens pre-solved in the background and hands
+// them out instantly, e.g. for the checkout flows which can not wait for a
+// solve when the user clicks. Size tokens are kept per page URL, site key and
+// action, a token is solved again when it is taken or has less than Margin
+// validity left. The tokens which age out unused are paid for too, the
+// tokens of a key are not pre-solved anymore once it was not requested for
+// IdleTimeout. It is safe for concurrent use.
+type TokenPool struct {
+	// Margin is the validity a token must have left when it is returned,
+	// DefaultFreshMargin if it is zero. It is capped to the half of
+	// DefaultTokenValidity.
+	Margin time.Duration
+	// IdleTimeout is the time the tokens of a key are pre-solved after the
+	// last Warm or Get of the key, DefaultTokenPoolIdleTimeout if it is zero
+	IdleTimeout time.Duration
+
+	client   *TwoCaptchaClient
+	size     int
+	minScore float64
+	opts     []SolveOption
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	queues map[tokenPoolKey]*tokenQueue
+}
+
+// tokenPoolKey identifies the tokens of a TokenPool
+type tokenPoolKey struct {
+	siteURL, siteKey, action string
+}
+
+// tokenQueue holds the pre-solved tokens of a key
+type tokenQueue struct {
+	tokens []pooledToken
+	// err is the error of the latest failed pre-solve
+	err error
+	// notify is closed when a token was added, a pre-solve failed or the
+	// queue was evicted
+	notify chan struct{}
+	// used is the time of the latest Warm or Get of the key
+	used time.Time
+}
+
+// pooledToken is a pre-solved token, taken is closed when it is handed out
+type pooledToken struct {
+	res   CaptchaResult
+	taken chan struct{}
+}
+
+// NewTokenPool creates a TokenPool keeping size tokens per key solved by c
+// with minScore and opts, see SolveRecaptchaV3Result
+func NewTokenPool(c *TwoCaptchaClient, size int, minScore float64, opts ...SolveOption) *TokenPool {
+	if size <= 0 {
+		size = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &TokenPool{
+		client:   c,
+		size:     size,
+		minScore: minScore,
+		opts:     opts,
+		ctx:      ctx,
+		cancel:   cancel,
+		queues:   make(map[tokenPoolKey]*tokenQueue),
+	}
+}
+
+// Warm starts pre-solving the tokens of a page URL, site key and action,
+// e.g. when the application starts, so the first Get does not wait
+func (p *TokenPool) Warm(siteURL, siteKey, action string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.ctx.Err() == nil {
+		p.queue(tokenPoolKey{siteURL, siteKey, action})
+	}
+}
+
+// Get returns a pre-solved token of a page URL, site key and action. The
+// tokens are pre-solved from the first call for the key if Warm was not
+// called. If no token is ready, Get waits for the next one until ctx is done.
+// The error of a failed pre-solve is returned if no token was ready,
+// ErrShutdown once the pool is closed.
+func (p *TokenPool) Get(ctx context.Context, siteURL, siteKey, action string) (CaptchaResult, error) {
+	k := tokenPoolKey{siteURL, siteKey, action}
+	for {
+		p.mu.Lock()
+		if p.ctx.Err() != nil {
+			p.mu.Unlock()
+			return CaptchaResult{}, ErrShutdown
+		}
+		q := p.queue(k)
+		for len(q.tokens) > 0 {
+			t := q.tokens[0]
+			q.tokens = q.tokens[1:]
+			close(t.taken)
+			if p.fresh(t.res) {
+				p.mu.Unlock()
+				return t.res, nil
+			}
+		}
+		notify := q.notify
+		p.mu.Unlock()
+
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return CaptchaResult{}, ctx.Err()
+		}
+		p.mu.Lock()
+		err := q.err
+		ready := len(q.tokens) > 0
+		p.mu.Unlock()
+		if !ready && err != nil {
+			return CaptchaResult{}, err
+		}
+	}
+}
+
+// Close stops pre-solving the tokens and waits for the pending solves
+func (p *TokenPool) Close() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+// queue returns the tokens of a key and starts pre-solving them if the key
+// is new. p.mu must be held.
+func (p *TokenPool) queue(k tokenPoolKey) *tokenQueue {
+	q, ok := p.queues[k]
+	if ok {
+		q.used = time.Now()
+		return q
+	}
+	q = &tokenQueue{notify: make(chan struct{}), used: time.Now()}
+	p.queues[k] = q
+	for i := 0; i < p.size; i++ {
+		p.wg.Add(1)
+		go p.fill(k, q)
+	}
+	return q
+}
+
+// fill keeps a token of a key pre-solved until the pool is closed
+func (p *TokenPool) fill(k tokenPoolKey, q *tokenQueue) {
+	defer p.wg.Done()
+	for p.ctx.Err() == nil && !p.evictIdle(k, q) {
+		res, err := p.client.SolveRecaptchaV3Result(p.ctx, k.siteURL, k.siteKey, k.action, p.minScore, p.opts...)
+		if err != nil {
+			if p.ctx.Err() != nil || errors.Is(err, ErrShutdown) {
+				return
+			}
+			p.client.logf("2captcha: pre-solving token of %s failed: %v", k.siteURL, err)
+			p.put(q, nil, err)
+			if sleep(p.ctx, tokenPoolRetryDelay) != nil {
+				return
+			}
+			continue
+		}
+
+		t := &pooledToken{res: res, taken: make(chan struct{})}
+		p.put(q, t, nil)
+		timer := time.NewTimer(time.Until(res.ExpiresAt()) - p.margin())
+		select {
+		case <-t.taken:
+		case <-timer.C:
+			p.remove(q, t)
+		case <-p.ctx.Done():
+		}
+		timer.Stop()
+	}
+}
+
+// put adds a token or the error of a failed pre-solve to a queue and wakes
+// up the waiting calls of Get
+func (p *TokenPool) put(q *tokenQueue, t *pooledToken, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if t != nil {
+		q.tokens = append(q.tokens, *t)
+	}
+	q.err = err
+	close(q.notify)
+	q.notify = make(chan struct{})
+}
+
+// remove drops an aged out token from a queue unless it was taken
+func (p *TokenPool) remove(q *tokenQueue, t *pooledToken) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, queued := range q.tokens {
+		if queued.taken == t.taken {
+			q.tokens = append(q.tokens[:i], q.tokens[i+1:]...)
+			return
+		}
+	}
+}
+
+// evictIdle drops the queue of a key which was not requested for
+// IdleTimeout and reports whether the pre-solves of q must stop. The
+// waiting calls of Get are woken up to pre-solve the key again.
+func (p *TokenPool) evictIdle(k tokenPoolKey, q *tokenQueue) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.queues[k] != q {
+		return true
+	}
+	if time.Since(q.used) < p.idleTimeout() {
+		return false
+	}
+	delete(p.queues, k)
+	q.err = nil
+	close(q.notify)
+	q.notify = make(chan struct{})
+	return true
+}
+
+// fresh reports whether a token has at least Margin validity left
+func (p *TokenPool) fresh(res CaptchaResult) bool {
+	return time.Until(res.ExpiresAt()) >= p.margin()
+}
+
+func (p *TokenPool) margin() time.Duration {
+	if p.Margin > maxTokenPoolMargin {
+		return maxTokenPoolMargin
+	}
+	if p.Margin > 0 {
+		return p.Margin
+	}
+	return DefaultFreshMargin
+}
+
+func (p *TokenPool) idleTimeout() time.Duration {
+	if p.IdleTimeout > 0 {
+		return p.IdleTimeout
+	}
+	return DefaultTokenPoolIdleTimeout
+}
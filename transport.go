@@ -0,0 +1,234 @@
+package twocaptcha
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// maxInterstitialSize is the largest response body inspected by Transport
+const maxInterstitialSize = 1 << 20
+
+var (
+	// dataDomePattern matches the dd object of the DataDome captcha pages
+	dataDomePattern      = regexp.MustCompile(`var\s+dd\s*=\s*\{([^}]*)\}`)
+	dataDomeFieldPattern = regexp.MustCompile(`['"]?(\w+)['"]?\s*:\s*(?:'([^']*)'|"([^"]*)"|([\w.-]+))`)
+	hCaptchaWallPattern  = regexp.MustCompile(`class\s*=\s*["'][^"']*\bh-captcha\b[^>]*data-sitekey\s*=\s*["']([\w-]+)["']|data-sitekey\s*=\s*["']([\w-]+)["'][^>]*class\s*=\s*["'][^"']*\bh-captcha\b`)
+	turnstileWallPattern = regexp.MustCompile(`class\s*=\s*["'][^"']*\bcf-turnstile\b[^>]*data-sitekey\s*=\s*["']([\w-]+)["']|data-sitekey\s*=\s*["']([\w-]+)["'][^>]*class\s*=\s*["'][^"']*\bcf-turnstile\b`)
+)
+
+// ErrDataDomeBlocked is returned by Transport if DataDome blocked the IP
+// address instead of showing a captcha. Use another proxy. It is unrelated
+// to the IP bans of 2captcha, see CodeIPBanned.
+var ErrDataDomeBlocked = errors.New("IP address is blocked by DataDome")
+
+// Transport is an http.RoundTripper solving the captchas of the responses
+// with Client and replaying the requests, e.g. for a colly Collector:
+//
+//	collector.WithTransport(&twocaptcha.Transport{Client: client, Proxy: proxy})
+//
+// The DataDome captcha pages are solved and the requests are replayed with
+// the datadome cookie, the cookie is added to the final response as a
+// Set-Cookie header for the cookie jar of the caller. The proxy of the
+// worker must be the proxy of Base, DataDome binds the cookie to the IP.
+//
+// The reCAPTCHA, hCaptcha and Turnstile widgets of the captcha walls are
+// solved if Replay is set, it builds the request submitting the token to the
+// site. ShouldSolve tells the walls from the pages with a widget in a form,
+// e.g. a login page, which are returned without paying for a token.
+// The Cloudflare challenge pages require a browser and are not solved.
+// Requests with a body are only replayed if their GetBody is set.
+type Transport struct {
+	// Client solves the captchas
+	Client *TwoCaptchaClient
+	// Base performs the requests, http.DefaultTransport if it is nil
+	Base http.RoundTripper
+	// Proxy is the proxy of Base passed to the workers, required by DataDome
+	Proxy *Proxy
+	// Replay returns the request submitting a token solved for the widget of
+	// resp, nil if the response must be returned unchanged
+	Replay func(req *http.Request, resp *http.Response, token string) (*http.Request, error)
+	// ShouldSolve reports whether the widget of resp is a captcha wall to be
+	// solved before the captcha is paid for. If it is nil, only the widgets
+	// of the responses with an error status, e.g. 403 or 429, are solved.
+	ShouldSolve func(req *http.Request, resp *http.Response) bool
+}
+
+// RoundTrip performs a request and solves the captcha of its response
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base().RoundTrip(req)
+	if err != nil || !isHTML(resp) {
+		return resp, err
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxInterstitialSize+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxInterstitialSize {
+		// not an interstitial, the rest of the body is read by the caller
+		resp.Body = &prefixedBody{Reader: io.MultiReader(bytes.NewReader(body), resp.Body), body: resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	if m := dataDomePattern.FindSubmatch(body); m != nil && resp.StatusCode == http.StatusForbidden {
+		return t.solveDataDome(req, resp, string(m[1]))
+	}
+	if t.Replay != nil && t.shouldSolve(req, resp) {
+		return t.solveWidget(req, resp, string(body))
+	}
+	return resp, nil
+}
+
+// shouldSolve reports whether the widget of resp is a captcha wall
+func (t *Transport) shouldSolve(req *http.Request, resp *http.Response) bool {
+	if t.ShouldSolve != nil {
+		return t.ShouldSolve(req, resp)
+	}
+	return resp.StatusCode >= http.StatusBadRequest
+}
+
+// solveDataDome solves a DataDome captcha page and replays the request
+func (t *Transport) solveDataDome(req *http.Request, resp *http.Response, dd string) (*http.Response, error) {
+	fields := map[string]string{}
+	for _, m := range dataDomeFieldPattern.FindAllStringSubmatch(dd, -1) {
+		fields[m[1]] = m[2] + m[3] + m[4]
+	}
+	if fields["t"] == "bv" {
+		resp.Body.Close()
+		return nil, ErrDataDomeBlocked
+	}
+	if fields["rt"] != "" && fields["rt"] != "c" {
+		// a device check, not a captcha
+		return resp, nil
+	}
+	host := fields["host"]
+	if host == "" {
+		host = "geo.captcha-delivery.com"
+	}
+	cid := fields["cid"]
+	if cookie, err := req.Cookie("datadome"); err == nil {
+		cid = cookie.Value
+	}
+	q := url.Values{}
+	q.Set("initialCid", fields["cid"])
+	q.Set("hash", fields["hsh"])
+	q.Set("cid", cid)
+	q.Set("t", fields["t"])
+	q.Set("referer", req.URL.String())
+	q.Set("s", fields["s"])
+	if e := fields["e"]; e != "" {
+		q.Set("e", e)
+	}
+	captchaURL := "https://" + host + "/captcha/?" + q.Encode()
+
+	delay, retries := t.Client.polling()
+	answer, _, err := t.Client.SolveDataDomeWithContext(req.Context(), captchaURL, req.URL.String(), req.UserAgent(), t.Proxy, delay, retries)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	cookies := (&http.Response{Header: http.Header{"Set-Cookie": {answer}}}).Cookies()
+	if len(cookies) == 0 {
+		resp.Body.Close()
+		return nil, errors.New("Invalid DataDome cookie: " + answer)
+	}
+
+	replay, err := cloneRequest(req)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	replay.Header.Del("Cookie")
+	for _, cookie := range req.Cookies() {
+		if cookie.Name != cookies[0].Name {
+			replay.AddCookie(cookie)
+		}
+	}
+	replay.AddCookie(&http.Cookie{Name: cookies[0].Name, Value: cookies[0].Value})
+	resp.Body.Close()
+	final, err := t.base().RoundTrip(replay)
+	if err != nil {
+		return nil, err
+	}
+	final.Header.Add("Set-Cookie", answer)
+	return final, nil
+}
+
+// solveWidget solves the captcha widget of an HTML page and sends the
+// request built by Replay
+func (t *Transport) solveWidget(req *http.Request, resp *http.Response, body string) (*http.Response, error) {
+	pageURL := req.URL.String()
+	delay, retries := t.Client.polling()
+	var token string
+	var err error
+	if m := hCaptchaWallPattern.FindStringSubmatch(body); m != nil {
+		token, _, err = t.Client.SolveHCaptchaWithContext(req.Context(), pageURL, m[1]+m[2], HCaptchaOptions{}, delay, retries)
+	} else if m := turnstileWallPattern.FindStringSubmatch(body); m != nil {
+		token, _, err = t.Client.SolveTurnstileWithContext(req.Context(), pageURL, m[1]+m[2], TurnstileOptions{}, delay, retries)
+	} else if siteKey, opts, extractErr := ExtractRecaptchaParams(body); extractErr == nil {
+		var res CaptchaResult
+		res, err = t.Client.solveRecaptcha(req.Context(), pageURL, siteKey, opts, delay, retries, nil)
+		token = res.Answer
+	} else {
+		return resp, nil
+	}
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	replay, err := t.Replay(req, resp, token)
+	if replay == nil && err == nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	return t.base().RoundTrip(replay)
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// prefixedBody is a response body whose beginning was already read
+type prefixedBody struct {
+	io.Reader
+	body io.ReadCloser
+}
+
+func (b *prefixedBody) Close() error {
+	return b.body.Close()
+}
+
+// isHTML reports whether resp is an HTML page
+func isHTML(resp *http.Response) bool {
+	return strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html")
+}
+
+// cloneRequest returns a copy of req with a fresh body for a replay
+func cloneRequest(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, errors.New("Request body can not be replayed")
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	return r, nil
+}
@@ -0,0 +1,57 @@
+package twocaptcha
+
+import (
+	"context"
+	"time"
+)
+
+// TurnstileOptions contains the optional parameters of a Cloudflare Turnstile solving request
+type TurnstileOptions struct {
+	// Action is the value of the data-action attribute or the action
+	// parameter of turnstile.render
+	Action string
+	// Data is the value of the data-cdata attribute or the cData
+	// parameter of turnstile.render
+	Data string
+	// PageData is the chlPageData parameter of turnstile.render,
+	// required on Cloudflare Challenge pages
+	PageData string
+	// UserAgent is the user agent the token is bound to on Challenge pages
+	UserAgent string
+}
+
+// SolveTurnstile performs a Cloudflare Turnstile solving request to 2captcha.com
+// and returns with the solved captcha and captcha ID if the request was successful.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#turnstile
+//
+// Deprecated: use the Solve of the solver package with a TurnstileTask.
+func (c *TwoCaptchaClient) SolveTurnstile(siteURL, siteKey string, opts TurnstileOptions, delay time.Duration, retries int, solveOpts ...SolveOption) (string, string, error) {
+	return c.SolveTurnstileWithContext(context.Background(), siteURL, siteKey, opts, delay, retries, solveOpts...)
+}
+
+// SolveTurnstileWithContext is SolveTurnstile with a context.
+// The solve is aborted when ctx is done.
+//
+// Deprecated: use the Solve of the solver package with a TurnstileTask.
+func (c *TwoCaptchaClient) SolveTurnstileWithContext(ctx context.Context, siteURL, siteKey string, opts TurnstileOptions, delay time.Duration, retries int, solveOpts ...SolveOption) (string, string, error) {
+	params, err := turnstileParams(siteURL, siteKey, opts)
+	if err != nil {
+		return "", "", err
+	}
+	res, err := c.solve(ctx, params, delay, retries, solveOpts)
+	return res.Answer, res.ID, err
+}
+
+// turnstileParams builds the API parameters of a Turnstile solving request
+// from a TurnstileTask
+func turnstileParams(siteURL, siteKey string, opts TurnstileOptions) (map[string]string, error) {
+	return taskParams(TurnstileTask{
+		WebsiteURL: siteURL,
+		WebsiteKey: siteKey,
+		Action:     opts.Action,
+		Data:       opts.Data,
+		PageData:   opts.PageData,
+		UserAgent:  opts.UserAgent,
+	})
+}
@@ -3,22 +3,42 @@ package twocaptcha
 // package twocaptcha provides a Golang client for https://2captcha.com/
 
 import (
+	"context"
 	"errors"
-	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
+	"sync"
 	"time"
 )
 
-// ApiURL is the url of the 2captcha API endpoint
+// ApiURL is the url of the 2captcha API endpoint used by the clients
+// without base URL.
+//
+// Deprecated: use WithBaseURL. ApiURL is read by New, changing it affects
+// the clients created afterwards.
 var ApiURL = "https://2captcha.com/in.php"
 
-// ResultURL is the url of the 2captcha result API endpoint
+// ResultURL is the url of the 2captcha result API endpoint used by the
+// clients without base URL.
+//
+// Deprecated: use WithBaseURL. ResultURL is read by New, changing it
+// affects the clients created afterwards.
 var ResultURL = "https://2captcha.com/res.php"
 
+// captchaIdPattern matches the captcha IDs returned by in.php
+var captchaIdPattern = regexp.MustCompile(`^[0-9]+$`)
+
 // TwoCaptchaClient is an interface to https://2captcha.com/ API.
+//
+// A client is safe for concurrent use by multiple goroutines. Its internal
+// state, e.g. the rate limiters, the budget, the spend counters and the key
+// pool, is synchronized. The exported fields must not be changed while the
+// client is in use. OnSpend, OnStatus, Metrics and Logger are called
+// concurrently from the goroutines of the solves.
 type TwoCaptchaClient struct {
 	// ApiKey is the API key for the 2captcha.com API.
 	// Valid key is required by all the functions of this library
@@ -26,135 +46,723 @@ type TwoCaptchaClient struct {
 	ApiKey string
 	// Client is a HTTP client for the api calls to 2captcha
 	Client *http.Client
+	// Doer performs the HTTP requests instead of Client if it is set,
+	// e.g. to mock the API in tests
+	Doer Doer
+	// SoftID is the ID of the software registered in the 2captcha developer
+	// program, it is sent with every submitted captcha. It overrides the
+	// SoftID of the ClientProfile and is overridden by WithSoftID.
+	SoftID string
+	// DryRun disables all network calls. Solves return a *DryRunError
+	// holding the exact form values that would have been submitted.
+	DryRun bool
+	// Proxy is passed to the workers of the captcha types supporting proxies
+	Proxy *Proxy
+	// AllowProxylessFallback retries a solve without Proxy if the proxied
+	// attempt failed because of the proxy. Some captcha types produce
+	// tokens that are rejected by the site when solved without the proxy.
+	AllowProxylessFallback bool
+	// ResultFormat is the format of the API responses. New sets JSONFormat,
+	// the responses are parsed more reliably and carry additional details of
+	// the solved captchas. TextFormat is the zero value.
+	ResultFormat ResultFormat
+	// OnSpend is called after every solved captcha with its cost.
+	// The cost is only reported by the API in JSONFormat.
+	OnSpend func(SpendEvent)
+	// OnStatus is called on every status change of a solved captcha, e.g. to
+	// observe the SUBMITTED, PENDING, SOLVED and FAILED transitions.
+	// It is called from the goroutine of the solve.
+	OnStatus func(captchaId string, status Status)
+	// OnBan is called when the client starts cooling down after an IP ban or
+	// a rate limit error of the API, e.g. to alert the operators. See Status.
+	OnBan func(ClientStatus)
+	// ResultErrorClassifier decides whether an ERROR_ code returned while
+	// polling res.php is retried or aborts the solve. The built-in
+	// classification is used if it is nil, see DefaultResultErrorClassifier.
+	ResultErrorClassifier func(code string) (retry bool)
+	// Metrics receives the measurements of the solves if it is set
+	Metrics Metrics
+	// Logger logs the retries of the client if it is set
+	Logger Logger
+	// Debug additionally logs the parameters of the requests and the raw
+	// responses to Logger. The API key, the proxy credentials and the pingback
+	// token are redacted.
+	Debug bool
+	// Backoff is the wait between the polls of a captcha. The delay passed
+	// to the solver functions is used between every poll if it is nil.
+	Backoff Backoff
+
+	baseURL           string
+	endpoints         *endpoints
+	apiURL            string
+	resURL            string
+	v2URL             string
+	taskURL           string
+	okPrefix          string
+	noTaskAPI         bool
+	sandbox           bool
+	rawAnswers        bool
+	profile           ClientProfile
+	pollInterval      time.Duration
+	maxWait           time.Duration
+	resubmits         int
+	banCooldown       time.Duration
+	ban               ClientStatus
+	httpTimeout       time.Duration
+	minPoll           time.Duration
+	idempotencyWindow time.Duration
+	claimed           map[string]bool
+	polls             map[string]time.Time
+	requestHooks      []func(*http.Request)
+	sites             map[string]SiteProfile
+	slots             *slots
+	budget            *budget
+	breaker           *breaker
+	store             TaskStore
+	keys              []string
+	cache             AnswerCache
+	spent             spendCounter
+	capture           *capture
+	cacheTTL          time.Duration
+	failedKeys        map[string]time.Time
+
+	submitLimiter *rateLimiter
+	resultLimiter *rateLimiter
+
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	inflight map[context.Context]context.CancelFunc
+	shutdown bool
+	paused   chan struct{}
+	drained  []CaptchaResult
+}
+
+// Doer performs HTTP requests, *http.Client implements it
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DryRunError is returned by the solver functions when DryRun is enabled.
+// Form contains the parameters that would have been posted to URL,
+// JSON the request body of the JSON API v2. The API key, the proxy
+// credentials and the pingback token are redacted in the message of the error.
+type DryRunError struct {
+	URL  string
+	Form url.Values
+	JSON []byte
+}
+
+func (e *DryRunError) Error() string {
+	if e.JSON != nil {
+		return "Dry run: " + e.URL + " " + redactJSON(e.JSON)
+	}
+	form := make(url.Values, len(e.Form))
+	for k, v := range e.Form {
+		form[k] = v
+	}
+	if form.Get("key") != "" {
+		form.Set("key", "REDACTED")
+	}
+	if proxy := form.Get("proxy"); proxy != "" {
+		form.Set("proxy", redactProxy(proxy))
+	}
+	if pingback := form.Get("pingback"); pingback != "" {
+		form.Set("pingback", redactPingback(pingback))
+	}
+	return "Dry run: " + e.URL + "?" + form.Encode()
+}
+
+// Option configures a TwoCaptchaClient created by New
+type Option func(*TwoCaptchaClient)
+
+// WithMaxConcurrent limits the number of captchas solved concurrently by
+// the client to n, e.g. to the limit of pending captchas of the account
+// instead of hitting ERROR_NO_SLOT_AVAILABLE. Further solves block until a
+// slot frees or their context is done, the freed slots are given by
+// WithPriority. The solves with WithoutQueueing fail with
+// ErrTooManyInFlight instead of waiting.
+func WithMaxConcurrent(n int) Option {
+	return func(c *TwoCaptchaClient) {
+		if n > 0 {
+			c.slots = newSlots(n)
+		}
+	}
+}
+
+// WithBaseURL sends the requests of the client to the in.php and res.php
+// of baseURL instead of https://2captcha.com, e.g. to a mock server in tests
+func WithBaseURL(baseURL string) Option {
+	return func(c *TwoCaptchaClient) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithTaskURL sends the JSON API v2 requests of the client to taskURL
+// instead of https://api.2captcha.com
+func WithTaskURL(taskURL string) Option {
+	return func(c *TwoCaptchaClient) {
+		c.taskURL = strings.TrimSuffix(taskURL, "/")
+	}
+}
+
+// WithPolling sets the polling interval of the solvers without delay
+// argument, 5 seconds by default. The interval is rounded down to seconds.
+func WithPolling(interval time.Duration) Option {
+	return func(c *TwoCaptchaClient) {
+		c.pollInterval = interval
+	}
+}
+
+// WithMaxWait limits the duration of every solve of the client to d unless
+// a solve sets its own limit with WithTimeout. ErrTimeout is returned when
+// it elapses, see WithTimeout. The solvers without retries
+// argument poll until d elapses instead of the default 20 times.
+func WithMaxWait(d time.Duration) Option {
+	return func(c *TwoCaptchaClient) {
+		c.maxWait = d
+	}
+}
+
+// WithRequestHook calls hook with every HTTP request of the client before it
+// is sent, e.g. to add tracing headers or to sign the requests for an egress
+// proxy. The hooks are called in the order they were added. The request
+// body must not be consumed, use req.GetBody to read it if it is set.
+func WithRequestHook(hook func(req *http.Request)) Option {
+	return func(c *TwoCaptchaClient) {
+		c.requestHooks = append(c.requestHooks, hook)
+	}
 }
 
-// New creates a TwoCaptchaClient instance
-func New(apiKey string) *TwoCaptchaClient {
-	return &TwoCaptchaClient{
-		ApiKey: apiKey,
-		Client: http.DefaultClient,
+// DefaultHTTPTimeout is the timeout of every HTTP request of the clients
+// created by New. It is independent of the deadline of the solves, a hung
+// poll is retried while the solve has time left.
+const DefaultHTTPTimeout = 30 * time.Second
+
+// WithDefaultLang sets the default language of the workers solving the
+// captchas of the client, e.g. "en". It is ClientProfile.DefaultLang, a
+// WithProfile option after it replaces it, WithLang overrides it per solve.
+func WithDefaultLang(lang string) Option {
+	return func(c *TwoCaptchaClient) {
+		c.profile.DefaultLang = lang
 	}
 }
 
-// SolveRecaptchaV2 performs a recaptcha v2 solving request to 2captcha.com
-// and returns with the solved captcha and captcha ID if the request was successful.
-// Valid ApiKey is required.
-// See more details on https://2captcha.com/2captcha-api#solving_recaptchav2_new
-func (c *TwoCaptchaClient) SolveRecaptchaV2(siteURL, recaptchaKey string, delay time.Duration, retries int) (string, string, error) {
-	captchaId, err := c.apiRequest(
-		ApiURL,
+// WithHTTPTimeout sets the timeout of every HTTP request of the client,
+// DefaultHTTPTimeout by default. It applies to the Doer too, zero disables
+// the timeout. The Timeout of the Client is applied in addition to it.
+func WithHTTPTimeout(d time.Duration) Option {
+	return func(c *TwoCaptchaClient) {
+		c.httpTimeout = d
+	}
+}
+
+// WithHTTPClient sends the requests of the client with client instead of
+// http.DefaultClient, e.g. to use a proxy or custom transport.
+// A nil client is ignored.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *TwoCaptchaClient) {
+		if client != nil {
+			c.Client = client
+		}
+	}
+}
+
+// New creates a TwoCaptchaClient instance.
+// The API key is not validated here to keep New infallible, API calls of
+// a client without API key fail with ErrAPIKeyRequired without reaching 2captcha.
+func New(apiKey string, opts ...Option) *TwoCaptchaClient {
+	c := &TwoCaptchaClient{
+		ApiKey:            apiKey,
+		Client:            http.DefaultClient,
+		ResultFormat:      JSONFormat,
+		httpTimeout:       DefaultHTTPTimeout,
+		minPoll:           DefaultMinPollInterval,
+		idempotencyWindow: DefaultIdempotencyWindow,
+		apiURL:            ApiURL,
+		resURL:            ResultURL,
+		v2URL:             TaskURL,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ReportBadCaptcha reports an incorrectly solved captcha to 2captcha.com.
+// It is reported with ApiKey, use ReportResult for the captchas solved with
+// the other keys of WithAPIKeys.
+// See more details on https://2captcha.com/2captcha-api#complain
+func (c *TwoCaptchaClient) ReportBadCaptcha(captchaId string) error {
+	return c.ReportBadCaptchaWithContext(context.Background(), captchaId)
+}
+
+// ReportBadCaptchaWithContext is ReportBadCaptcha with a context
+func (c *TwoCaptchaClient) ReportBadCaptchaWithContext(ctx context.Context, captchaId string) error {
+	return c.report(ctx, captchaId, "reportbad")
+}
+
+// ReportGoodCaptcha reports a correctly solved captcha to 2captcha.com.
+// It is reported with ApiKey, use ReportResult for the captchas solved with
+// the other keys of WithAPIKeys.
+// See more details on https://2captcha.com/2captcha-api#complain
+func (c *TwoCaptchaClient) ReportGoodCaptcha(captchaId string) error {
+	return c.ReportGoodCaptchaWithContext(context.Background(), captchaId)
+}
+
+// ReportGoodCaptchaWithContext is ReportGoodCaptcha with a context
+func (c *TwoCaptchaClient) ReportGoodCaptchaWithContext(ctx context.Context, captchaId string) error {
+	return c.report(ctx, captchaId, "reportgood")
+}
+
+func (c *TwoCaptchaClient) report(ctx context.Context, captchaId, action string) error {
+	ctx, done, err := c.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	_, err = c.apiRequest(
+		ctx,
+		c.resultURL(),
 		map[string]string{
-			"googlekey": recaptchaKey,
-			"pageurl":   siteURL,
-			"method":    "userrecaptcha",
+			"id":     captchaId,
+			"action": action,
 		},
 		0,
 		3,
 	)
 
+	return err
+}
+
+// solve submits a captcha to in.php and polls res.php for the answer.
+// The solve options are applied to params and Proxy is attached to
+// the request if it is set on the client or on the solve.
+func (c *TwoCaptchaClient) solve(ctx context.Context, params map[string]string, delay time.Duration, retries int, opts []SolveOption) (CaptchaResult, error) {
+	ctx, done, err := c.begin(ctx)
 	if err != nil {
-		return "", "", err
+		return CaptchaResult{}, err
 	}
+	defer done()
 
-	time.Sleep(10 * time.Second)
+	o := c.solveOptions(opts)
+	params = o.params(params)
+	delay = o.delay(delay)
+	if err := validateParams(params); err != nil {
+		return CaptchaResult{}, err
+	}
+	parent := ctx
+	ctx, cancel := o.context(ctx)
+	defer cancel()
 
-	resp, err := c.apiRequest(
-		ResultURL,
-		map[string]string{
-			"googlekey": recaptchaKey,
-			"pageurl":   siteURL,
-			"method":    "userrecaptcha",
-			"id":        captchaId,
-			"action":    "get",
-		},
-		delay,
-		retries,
-	)
-	return resp, captchaId, err
+	proxy := c.Proxy
+	if o.proxy != nil {
+		proxy = o.proxy
+	}
+	if proxy == nil {
+		res, err := c.solveOnce(ctx, params, delay, retries)
+		return res, timeout(parent, ctx, res.ID, err)
+	}
+	proxied := make(map[string]string, len(params)+2)
+	for k, v := range params {
+		proxied[k] = v
+	}
+	proxied["proxy"] = proxy.String()
+	proxied["proxytype"] = proxy.Type
+	res, err := c.solveOnce(ctx, proxied, delay, retries)
+	if err != nil && c.AllowProxylessFallback && isProxyError(err) {
+		c.logf("2captcha: proxied solve failed, retrying without proxy: %v", err)
+		res, err = c.solveOnce(ctx, params, delay, retries)
+		res.ProxylessFallback = true
+	}
+	return res, timeout(parent, ctx, res.ID, err)
 }
 
-// SolveRecaptchaV3 performs a recaptcha v3 solving request to 2captcha.com
-// and returns with the solved captcha if the request was successful.
-// Valid ApiKey is required.
-// See more details on https://2captcha.com/solving_recaptcha_v3
-func (c *TwoCaptchaClient) SolveRecaptchaV3(siteURL, recaptchaKey, action string, minScore float64) (string, error) {
-	captchaId, err := c.apiRequest(
-		ApiURL,
-		map[string]string{
-			"googlekey": recaptchaKey,
-			"pageurl":   siteURL,
-			"method":    "userrecaptcha",
-			"version":   "v3",
-			"action":    action,
-			"min_score": fmt.Sprintf("%.1f", minScore),
-		},
-		0,
-		3,
-	)
+// solveProxyless is solve for the captcha types solved from an image or
+// other data sent with the request, they are never solved through a proxy
+func (c *TwoCaptchaClient) solveProxyless(ctx context.Context, params map[string]string, delay time.Duration, retries int, opts []SolveOption, files ...file) (CaptchaResult, error) {
+	ctx, done, err := c.begin(ctx)
+	if err != nil {
+		return CaptchaResult{}, err
+	}
+	defer done()
+
+	o := c.solveOptions(opts)
+	params = o.params(params)
+	delay = o.delay(delay)
+	if err := validateParams(params); err != nil {
+		return CaptchaResult{}, err
+	}
+	parent := ctx
+	ctx, cancel := o.context(ctx)
+	defer cancel()
+	res, err := c.solveOnce(ctx, params, delay, retries, files...)
+	return res, timeout(parent, ctx, res.ID, err)
+}
 
+// solveOnce submits a captcha and waits for its answer, it is resubmitted
+// if it was unsolvable and the client resubmits the unsolvable captchas
+func (c *TwoCaptchaClient) solveOnce(ctx context.Context, params map[string]string, delay time.Duration, retries int, files ...file) (CaptchaResult, error) {
+	release, captchaId, err := c.claimKey(ctx)
 	if err != nil {
-		return "", err
+		return CaptchaResult{ID: captchaId}, err
 	}
+	defer release()
 
-	return c.apiRequest(
-		ResultURL,
-		map[string]string{
-			"googlekey": recaptchaKey,
-			"pageurl":   siteURL,
-			"method":    "userrecaptcha",
-			"id":        captchaId,
-			"action":    "get",
-		},
-		5,
-		20,
-	)
+	return c.resubmit(ctx, rewindable(files), func() (CaptchaResult, error) {
+		return c.solveAttempt(ctx, params, delay, retries, files...)
+	})
 }
 
-func (c *TwoCaptchaClient) ReportBadCaptcha(captchaId string) (error) {
-	_, err := c.apiRequest(
-		ResultURL,
+func (c *TwoCaptchaClient) solveAttempt(ctx context.Context, params map[string]string, delay time.Duration, retries int, files ...file) (CaptchaResult, error) {
+	if err := c.acquire(ctx); err != nil {
+		return CaptchaResult{}, err
+	}
+	defer c.release()
+
+	submitted := time.Now()
+	method := captchaMethod(params)
+	ctx, captchaId, err := c.failover(ctx, rewindable(files), func(ctx context.Context) (string, error) {
+		return c.submit(ctx, params, files...)
+	})
+	if err != nil {
+		c.metricFailed(ctx, method, err)
+		return CaptchaResult{}, err
+	}
+	c.status(captchaId, StatusSubmitted)
+	c.metricSubmitted(ctx, method)
+	task := StoredTask{ID: captchaId, APIKey: c.poolKey(ctx), Method: method, Params: storedParams(params), SubmittedAt: submitted, Tags: tags(ctx), IdempotencyKey: idempotency(ctx)}
+	c.storeTask(task)
+
+	resp, err := c.result(ctx, captchaId, method, delay, retries)
+	c.unstoreTask(ctx, task, err)
+	if err != nil {
+		c.status(captchaId, StatusFailed)
+		c.metricFailed(ctx, method, err)
+		// the key is kept to report the captcha to the account which paid for it
+		return CaptchaResult{ID: captchaId, APIKey: c.key(ctx)}, err
+	}
+	c.status(captchaId, StatusSolved)
+	res := c.solved(ctx, captchaId, resp)
+	c.normalizeAnswer(method, &res)
+	res.APIKey = c.key(ctx)
+	res.SubmittedAt = submitted
+	res.SolveDuration = time.Since(submitted)
+	c.metricSolved(ctx, method, res)
+	c.drain(res)
+	return res, nil
+}
+
+// result waits for the answer of a submitted captcha. It polls res.php
+// unless the solve waits for the answer on a PingbackServer.
+func (c *TwoCaptchaClient) result(ctx context.Context, captchaId, method string, delay time.Duration, retries int) (*response, error) {
+	initialWait := c.initialWait(ctx, method)
+	if s, ok := ctx.Value(pingbackKey{}).(*PingbackServer); ok {
+		// wait as long as polling would have taken
+		return s.wait(ctx, captchaId, initialWait+time.Duration(retries)*delay*time.Second)
+	}
+
+	if err := sleep(ctx, initialWait); err != nil {
+		return nil, err
+	}
+
+	return c.apiRequest(
+		ctx,
+		c.resultURL(),
 		map[string]string{
 			"id":     captchaId,
-			"action": "reportbad",
+			"action": c.getAction(ctx),
 		},
-		0,
-		3,
+		delay,
+		pollAttempts(ctx, delay, retries),
 	)
+}
 
-	return err
+// submit submits a captcha to in.php and returns its captcha ID.
+// files are uploaded with a multipart method=post request.
+func (c *TwoCaptchaClient) submit(ctx context.Context, params map[string]string, files ...file) (string, error) {
+	if err := c.admit(ctx); err != nil {
+		return "", err
+	}
+	if err := c.checkBudget(ctx); err != nil {
+		return "", err
+	}
+	if !c.breaker.allow() {
+		return "", ErrProviderUnavailable
+	}
+	params = c.clientProfile().params(params)
+	var res *response
+	var err error
+	if len(files) > 0 {
+		res, err = c.upload(ctx, c.submitURL(), params, files)
+	} else {
+		res, err = c.apiRequest(ctx, c.submitURL(), params, 0, 3)
+	}
+	c.breaker.record(err)
+	if err != nil {
+		return "", err
+	}
+	if !captchaIdPattern.MatchString(res.Answer) {
+		return "", errors.New("Invalid captcha ID in submit response: " + res.Answer)
+	}
+	return res.Answer, nil
 }
 
-func (c *TwoCaptchaClient) apiRequest(URL string, params map[string]string, delay time.Duration, retries int) (string, error) {
-	if retries <= 0 {
-		return "", errors.New("Maximum retries exceeded")
+// apiRequest performs an API request up to retries times until the captcha
+// is ready, waiting between the attempts. ErrTimeout is returned if the
+// captcha was not ready after the last attempt, the error of the last
+// attempt if it failed.
+func (c *TwoCaptchaClient) apiRequest(ctx context.Context, URL string, params map[string]string, delay time.Duration, retries int) (*response, error) {
+	err := ErrTimeout
+	for attempt := 1; attempt <= retries; attempt++ {
+		if attempt > 1 {
+			if err := sleep(ctx, c.wait(delay, attempt-1)); err != nil {
+				return nil, err
+			}
+		}
+		var res *response
+		res, err = c.do(ctx, URL, params)
+		if err == ErrNotReady {
+			// the captcha is still being solved, poll the same ID again
+			c.status(params["id"], StatusPending)
+			c.debugf("2captcha: captcha %s is not ready, attempt %d of %d", params["id"], attempt, retries)
+			continue
+		}
+		if isTransient(err) && URL == c.resultURL() && ctx.Err() == nil {
+			// res.php is idempotent, in.php is never retried to avoid
+			// paying for the same captcha twice
+			c.logf("2captcha: retrying %s, attempt %d of %d: %v", URL, attempt, retries, err)
+			continue
+		}
+		if apiErr, ok := err.(*APIError); ok && URL == c.resultURL() && c.retryResultError(apiErr.Code) {
+			c.logf("2captcha: retrying %s, attempt %d of %d: %v", URL, attempt, retries, err)
+			continue
+		}
+		if res != nil {
+			res.Polls = attempt
+		}
+		return res, err
 	}
-	time.Sleep(delay * time.Second)
-	form := url.Values{}
-	form.Add("key", c.ApiKey)
-	for k, v := range params {
-		form.Add(k, v)
+	if err == ErrNotReady {
+		return nil, &TimeoutError{CaptchaID: params["id"]}
+	}
+	return nil, err
+}
+
+// do performs a single API request and checks its response.
+// ErrNotReady is returned if the captcha is not solved yet.
+func (c *TwoCaptchaClient) do(ctx context.Context, URL string, params map[string]string) (*response, error) {
+	form := c.form(ctx, params)
+	if c.DryRun {
+		return nil, &DryRunError{URL: URL, Form: form}
+	}
+	if URL == c.resultURL() && isPoll(params) {
+		if err := c.pollGate(ctx, params["id"]); err != nil {
+			return nil, err
+		}
 	}
 
 	req, err := http.NewRequest("POST", URL, strings.NewReader(form.Encode()))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	resp, err := c.Client.Do(req)
+	res, err := c.send(ctx, req, params)
+	if URL == c.resultURL() && isPoll(params) {
+		c.polled(params["id"], err)
+	}
+	return res, err
+}
+
+// send sends an API request and checks its response
+func (c *TwoCaptchaClient) send(ctx context.Context, req *http.Request, params map[string]string) (*response, error) {
+	if err := c.cooldown(ctx, req.URL.String() == c.submitURL()); err != nil {
+		return nil, err
+	}
+	body, err := c.roundTrip(ctx, req, params)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	body, err := ioutil.ReadAll(resp.Body)
+	res, err := parseResponse(body, c.format(ctx), c.okPrefix)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	if res.Answer == "CAPCHA_NOT_READY" {
+		return nil, ErrNotReady
+	}
+	// only the reports of res.php answer OK_REPORT_RECORDED
+	endpoint := req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+	action := params["action"]
+	isReport := endpoint == c.resultURL() && (action == "reportbad" || action == "reportgood")
+	if !res.OK || (isReport && res.Answer != "OK_REPORT_RECORDED") {
+		err := &APIError{Code: res.Answer, Description: res.ErrorText}
+		c.noteBan(err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// roundTrip sends an API request and returns the body of its response
+func (c *TwoCaptchaClient) roundTrip(ctx context.Context, req *http.Request, params map[string]string) ([]byte, error) {
+	// the query is not part of the endpoint of the rate limits
+	endpoint := req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+	if err := c.limit(ctx, endpoint); err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	c.debugf("2captcha request: %s %s", redactURL(req.URL), redact(params))
+	exchange := Exchange{Time: time.Now(), URL: redactURL(req.URL), Request: redact(params)}
+	// only the requests of res.php can be sent again, in.php would pay for
+	// the captcha twice
+	resp, err := c.httpDoEndpoint(req, req.Method == "GET" || endpoint == c.resultURL())
+	if err != nil {
+		exchange.Err = err
+		c.record(exchange)
+		return nil, &transientError{err}
+	}
+	body, err := ioutil.ReadAll(resp.Body)
 	resp.Body.Close()
-	if strings.Contains(string(body), "CAPCHA_NOT_READY") {
-		return c.apiRequest(URL, params, delay, retries-1)
+	exchange.StatusCode, exchange.Response, exchange.Err = resp.StatusCode, body, err
+	c.record(exchange)
+	if err != nil {
+		return nil, &transientError{err}
+	}
+	c.debugf("2captcha response: %s %s", resp.Status, truncate(string(body)))
+	if resp.StatusCode >= 500 {
+		return nil, &transientError{errors.New("Server error: " + resp.Status)}
+	}
+	return body, nil
+}
+
+// submitURL returns the URL of in.php
+func (c *TwoCaptchaClient) submitURL() string {
+	if c.baseURL != "" {
+		return c.baseURL + "/in.php"
+	}
+	if c.apiURL != "" {
+		return c.apiURL
 	}
-	if (params["action"] == "reportbad" && string(body) != "OK_REPORT_RECORDED") || (params["action"] != "reportbad" && !strings.Contains(string(body), "OK|")) {
-		return "", errors.New("Invalid respponse from 2captcha: " + string(body))
+	return ApiURL
+}
+
+// resultURL returns the URL of res.php
+func (c *TwoCaptchaClient) resultURL() string {
+	if c.baseURL != "" {
+		return c.baseURL + "/res.php"
+	}
+	if c.resURL != "" {
+		return c.resURL
+	}
+	return ResultURL
+}
+
+// httpDo performs a HTTP request with the Doer or the Client of the client
+func (c *TwoCaptchaClient) httpDo(req *http.Request) (*http.Response, error) {
+	cancel := func() {}
+	if c.httpTimeout > 0 {
+		var ctx context.Context
+		ctx, cancel = context.WithTimeout(req.Context(), c.httpTimeout)
+		req = req.WithContext(ctx)
+	}
+	for _, hook := range c.requestHooks {
+		hook(req)
+	}
+	var resp *http.Response
+	var err error
+	switch {
+	case c.Doer != nil:
+		resp, err = c.Doer.Do(req)
+	case c.Client != nil:
+		resp, err = c.Client.Do(req)
+	default:
+		resp, err = http.DefaultClient.Do(req)
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// the timeout covers reading the body too
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody is a response body canceling the context of its request on Close
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// form builds the form values of an API request
+func (c *TwoCaptchaClient) form(ctx context.Context, params map[string]string) url.Values {
+	form := url.Values{}
+	form.Add("key", c.key(ctx))
+	if c.format(ctx) == JSONFormat {
+		form.Add("json", "1")
+	}
+	for k, v := range params {
+		form.Add(k, v)
+	}
+	return form
+}
+
+// acquire waits for a free solving slot if the concurrency of the client is limited
+func (c *TwoCaptchaClient) acquire(ctx context.Context) error {
+	if c.slots == nil {
+		return nil
+	}
+	return c.slots.acquire(ctx, priority(ctx))
+}
+
+// release frees the solving slot taken by acquire
+func (c *TwoCaptchaClient) release() {
+	if c.slots != nil {
+		c.slots.release()
+	}
+}
+
+// polling returns the delay and the number of polls of the solvers without
+// delay and retries arguments
+func (c *TwoCaptchaClient) polling() (delay time.Duration, retries int) {
+	delay, retries = 5, 20
+	if c.pollInterval >= time.Second {
+		delay = c.pollInterval / time.Second
+	}
+	if c.maxWait > 0 {
+		if retries = int(c.maxWait / (delay * time.Second)); retries < 1 {
+			retries = 1
+		}
+	}
+	return delay, retries
+}
+
+// pollAttempts returns the number of polls fitting into the deadline of ctx
+// with delay seconds between them. retries is returned if ctx has no deadline.
+func pollAttempts(ctx context.Context, delay time.Duration, retries int) int {
+	deadline, ok := ctx.Deadline()
+	if !ok || delay <= 0 {
+		return retries
+	}
+	n := int(time.Until(deadline) / (delay * time.Second))
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// sleep pauses for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	return string(body[3:]), nil
 }
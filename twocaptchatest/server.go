@@ -0,0 +1,239 @@
+// Package twocaptchatest provides a fake 2captcha server for testing the
+// code using the twocaptcha client without calling the paid API.
+package twocaptchatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Server is a fake 2captcha server implementing in.php, res.php and the
+// createTask and getTaskResult methods of the JSON API v2.
+// The fields are set before the requests are sent, or changed with Update
+// while the requests may be in flight. The server is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	// Polls is the number of polls answered with CAPCHA_NOT_READY
+	// before a captcha is solved
+	Polls int
+	// Delay is the time after the submission before a captcha is solved,
+	// the polls are answered with CAPCHA_NOT_READY meanwhile. Delay and
+	// Polls both have to pass.
+	Delay time.Duration
+	// Answer is the answer of the solved captchas, "TOKEN" by default
+	Answer string
+	// SubmitError is returned by in.php instead of a captcha ID if it is set,
+	// e.g. ERROR_ZERO_BALANCE
+	SubmitError string
+	// ResultError is returned by res.php instead of the answer if it is set,
+	// e.g. ERROR_CAPTCHA_UNSOLVABLE
+	ResultError string
+	// Balance is the balance returned by action=getbalance
+	Balance float64
+	// Price is the price of the solved captchas returned by action=get2
+	Price float64
+	// KeyErrors are the errors returned by in.php for some API keys instead
+	// of a captcha ID, e.g. ERROR_ZERO_BALANCE for the key of an empty account
+	KeyErrors map[string]string
+
+	mu        sync.Mutex
+	nextID    int
+	polls     map[string]int
+	submitted map[string]time.Time
+	requests  []url.Values
+}
+
+// NewServer starts a fake 2captcha server, it must be closed with Close
+func NewServer() *Server {
+	s := &Server{
+		Answer:    "TOKEN",
+		polls:     make(map[string]int),
+		submitted: make(map[string]time.Time),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Update calls f with the server locked, f changes the fields of the server
+// without racing with the requests in flight, e.g.
+//
+//	s.Update(func(s *twocaptchatest.Server) { s.SubmitError = "" })
+func (s *Server) Update(f func(s *Server)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f(s)
+}
+
+// Client returns a HTTP client sending all the requests to the server
+// regardless of their host, it can be used as the Client of a
+// TwoCaptchaClient without changing the API URLs. Alternatively the client
+// can be created with the WithBaseURL(s.URL) and WithTaskURL(s.URL) options.
+func (s *Server) Client() *http.Client {
+	u, _ := url.Parse(s.URL)
+	return &http.Client{Transport: &redirectTransport{target: u}}
+}
+
+// Requests returns the form values of the requests received by the server
+func (s *Server) Requests() []url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]url.Values(nil), s.requests...)
+}
+
+// redirectTransport sends the requests to the target host
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	r.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/in.php", "/res.php":
+		if err := r.ParseMultipartForm(10 << 20); err != nil && err != http.ErrNotMultipart {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Form)
+		s.mu.Unlock()
+		ok, answer := s.legacy(r.URL.Path, r.Form)
+		s.mu.Lock()
+		price := s.Price
+		s.mu.Unlock()
+		if r.Form.Get("action") != "get2" || answer == "CAPCHA_NOT_READY" {
+			price = 0
+		}
+		writeLegacy(w, r.Form.Get("json") == "1", ok, answer, price)
+	case "/createTask", "/getTaskResult", "/reportIncorrect", "/reportCorrect":
+		var req map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(s.task(r.URL.Path, req))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// legacy answers an in.php or res.php request
+func (s *Server) legacy(path string, form url.Values) (bool, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if path == "/in.php" {
+		if code := s.KeyErrors[form.Get("key")]; code != "" {
+			return false, code
+		}
+		if s.SubmitError != "" {
+			return false, s.SubmitError
+		}
+		return true, s.submit()
+	}
+	switch form.Get("action") {
+	case "get", "get2":
+		return s.poll(form.Get("id"))
+	case "reportbad", "reportgood":
+		return true, "OK_REPORT_RECORDED"
+	case "getbalance":
+		return true, strconv.FormatFloat(s.Balance, 'f', -1, 64)
+	}
+	return false, "ERROR_WRONG_ACTION"
+}
+
+// task answers a JSON API v2 request
+func (s *Server) task(path string, req map[string]interface{}) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if path == "/createTask" {
+		if code := s.KeyErrors[fmt.Sprint(req["clientKey"])]; code != "" {
+			return map[string]interface{}{"errorId": 1, "errorCode": code}
+		}
+		if s.SubmitError != "" {
+			return map[string]interface{}{"errorId": 1, "errorCode": s.SubmitError}
+		}
+		id, _ := strconv.Atoi(s.submit())
+		return map[string]interface{}{"errorId": 0, "taskId": id}
+	}
+	if path == "/reportIncorrect" || path == "/reportCorrect" {
+		return map[string]interface{}{"errorId": 0, "status": "success"}
+	}
+	id := fmt.Sprint(req["taskId"])
+	ok, answer := s.poll(id)
+	switch {
+	case !ok:
+		return map[string]interface{}{"errorId": 1, "errorCode": answer}
+	case answer == "CAPCHA_NOT_READY":
+		return map[string]interface{}{"errorId": 0, "status": "processing"}
+	}
+	return map[string]interface{}{
+		"errorId": 0,
+		"status":  "ready",
+		"solution": map[string]string{
+			"gRecaptchaResponse": answer,
+			"token":              answer,
+			"text":               answer,
+		},
+	}
+}
+
+// submit creates a captcha and returns its ID
+func (s *Server) submit() string {
+	s.nextID++
+	id := strconv.Itoa(s.nextID)
+	s.polls[id] = 0
+	s.submitted[id] = time.Now()
+	return id
+}
+
+// poll returns the answer of a captcha
+func (s *Server) poll(id string) (bool, string) {
+	n, ok := s.polls[id]
+	if !ok {
+		return false, "ERROR_WRONG_CAPTCHA_ID"
+	}
+	if s.ResultError != "" {
+		return false, s.ResultError
+	}
+	if n < s.Polls || time.Since(s.submitted[id]) < s.Delay {
+		s.polls[id] = n + 1
+		return true, "CAPCHA_NOT_READY"
+	}
+	return true, s.Answer
+}
+
+// writeLegacy writes a response of the legacy API in text or JSON format,
+// a non-zero price is only sent in JSON format
+func writeLegacy(w http.ResponseWriter, asJSON, ok bool, answer string, price float64) {
+	if asJSON {
+		status := 0
+		if ok && answer != "CAPCHA_NOT_READY" {
+			status = 1
+		}
+		res := map[string]interface{}{"status": status, "request": answer}
+		if ok && price > 0 {
+			res["price"] = strconv.FormatFloat(price, 'f', -1, 64)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+	if ok && answer != "CAPCHA_NOT_READY" && answer != "OK_REPORT_RECORDED" {
+		answer = "OK|" + answer
+	}
+	fmt.Fprint(w, answer)
+}
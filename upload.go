@@ -0,0 +1,71 @@
+package twocaptcha
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+)
+
+// file is a file uploaded with a multipart method=post request
+type file struct {
+	// field is the name of the form field, e.g. file or imginstructions
+	field string
+	// name is the file name sent to the API
+	name string
+	data []byte
+	// reader is streamed instead of data if it is set
+	reader io.Reader
+}
+
+// upload performs a single multipart API request uploading files.
+// The request body is streamed, the files are not buffered in memory.
+// DryRunError only contains the form values, not the files.
+func (c *TwoCaptchaClient) upload(ctx context.Context, URL string, params map[string]string, files []file) (*response, error) {
+	form := c.form(ctx, params)
+	if c.DryRun {
+		return nil, &DryRunError{URL: URL, Form: form}
+	}
+
+	pr, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeMultipart(w, form, files))
+	}()
+	// the pipe is closed if the request fails before the body is sent
+	defer pr.Close()
+
+	req, err := http.NewRequest("POST", URL, pr)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", w.FormDataContentType())
+	return c.send(ctx, req, params)
+}
+
+// writeMultipart writes the form values and the files to w and closes it
+func writeMultipart(w *multipart.Writer, form url.Values, files []file) error {
+	for k, vs := range form {
+		for _, v := range vs {
+			if err := w.WriteField(k, v); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range files {
+		part, err := w.CreateFormFile(f.field, f.name)
+		if err != nil {
+			return err
+		}
+		r := f.reader
+		if r == nil {
+			r = bytes.NewReader(f.data)
+		}
+		if _, err := io.Copy(part, r); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
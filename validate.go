@@ -0,0 +1,91 @@
+package twocaptcha
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+)
+
+// ErrInvalidTask is returned before submitting a captcha with invalid
+// parameters, e.g. an empty site key, so no doomed captcha is paid for.
+// The returned errors are *InvalidTaskError values, use errors.Is to check
+// them, e.g. errors.Is(err, twocaptcha.ErrInvalidTask)
+var ErrInvalidTask = errors.New("Invalid task")
+
+// InvalidTaskError is an invalid parameter of a captcha
+type InvalidTaskError struct {
+	// Field is the name of the parameter
+	Field string
+	// Reason describes the problem
+	Reason string
+}
+
+func (e *InvalidTaskError) Error() string {
+	return "Invalid task: " + e.Field + " " + e.Reason
+}
+
+// Is reports whether target is ErrInvalidTask
+func (e *InvalidTaskError) Is(target error) bool {
+	return target == ErrInvalidTask
+}
+
+// validatedTask is a Task checking its fields before it is submitted
+type validatedTask interface {
+	Task
+	validate() error
+}
+
+// validateTask checks the fields of a task if it supports validation
+func validateTask(task Task) error {
+	if task == nil {
+		return &InvalidTaskError{Field: "task", Reason: "is nil"}
+	}
+	if t, ok := task.(validatedTask); ok {
+		return t.validate()
+	}
+	return nil
+}
+
+// siteKeyPattern matches the site keys of the captcha providers, e.g. the
+// reCAPTCHA keys, the hCaptcha UUIDs, the Turnstile 0x keys and the base64
+// Amazon WAF keys
+var siteKeyPattern = regexp.MustCompile(`^[\w.:/+=-]+$`)
+
+// validateSiteKey checks a site key
+func validateSiteKey(field, key string) error {
+	if key == "" {
+		return &InvalidTaskError{Field: field, Reason: "is required"}
+	}
+	if !siteKeyPattern.MatchString(key) {
+		return &InvalidTaskError{Field: field, Reason: "is not a valid site key: " + key}
+	}
+	return nil
+}
+
+// validatePageURL checks the URL of the page with the captcha. The workers
+// open the page, so it must be an absolute http or https URL.
+func validatePageURL(field, pageURL string) error {
+	if pageURL == "" {
+		return &InvalidTaskError{Field: field, Reason: "is required"}
+	}
+	u, err := url.Parse(pageURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &InvalidTaskError{Field: field, Reason: "is not an absolute http(s) URL, see PageURLAddScheme: " + pageURL}
+	}
+	return nil
+}
+
+// validateParams checks the site key and the page URL of the in.php parameters
+func validateParams(params map[string]string) error {
+	for _, field := range []string{"googlekey", "sitekey", "publickey"} {
+		if key, ok := params[field]; ok {
+			if err := validateSiteKey(field, key); err != nil {
+				return err
+			}
+		}
+	}
+	if pageURL, ok := params["pageurl"]; ok {
+		return validatePageURL("pageurl", pageURL)
+	}
+	return nil
+}
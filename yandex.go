@@ -0,0 +1,20 @@
+package twocaptcha
+
+import "context"
+
+// SolveYandexSmartCaptcha performs a Yandex SmartCaptcha solving request to
+// 2captcha.com and returns with the solved captcha if the request was
+// successful, the token is its Answer. siteKey is the data-sitekey of the
+// captcha on pageURL.
+// Valid ApiKey is required.
+// See more details on https://2captcha.com/2captcha-api#yandex
+func (c *TwoCaptchaClient) SolveYandexSmartCaptcha(ctx context.Context, siteKey, pageURL string, opts ...SolveOption) (CaptchaResult, error) {
+	params := map[string]string{
+		"sitekey": siteKey,
+		"pageurl": pageURL,
+		"method":  "yandex",
+	}
+
+	delay, retries := c.polling()
+	return c.solve(ctx, params, delay, retries, opts)
+}